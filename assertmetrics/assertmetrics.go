@@ -0,0 +1,275 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+// Package assertmetrics provides testify style assertions for the mock
+// metrics in mockitmetrics.
+//
+// Each assertion reports a readable listing of every series in the metric
+// when it fails, so the difference between what was wanted and what was
+// recorded is obvious.
+package assertmetrics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/mockitmetrics"
+)
+
+type tHelper interface {
+	Helper()
+}
+
+// CounterEquals asserts that the counter series with exactly the provided
+// label name and value pairs has the wanted value.
+func CounterEquals(t assert.TestingT, c *mockitmetrics.Counter, want float64, labelValues ...string) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+
+	got, ok := c.Get(labelValues...)
+	if !ok {
+		return fail(t, c, "no counter series %s", selector(labelValues))
+	}
+	if got != want {
+		return fail(t, c, "counter series %s: want %s, got %s",
+			selector(labelValues), formatFloat(want), formatFloat(got))
+	}
+
+	return true
+}
+
+// GaugeEquals asserts that the gauge series with exactly the provided label
+// name and value pairs has the wanted value.
+func GaugeEquals(t assert.TestingT, g *mockitmetrics.Gauge, want float64, labelValues ...string) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+
+	got, ok := g.Get(labelValues...)
+	if !ok {
+		return fail(t, g, "no gauge series %s", selector(labelValues))
+	}
+	if got != want {
+		return fail(t, g, "gauge series %s: want %s, got %s",
+			selector(labelValues), formatFloat(want), formatFloat(got))
+	}
+
+	return true
+}
+
+// HistogramCount asserts that the histogram series with exactly the provided
+// label name and value pairs has the wanted number of observations.
+func HistogramCount(t assert.TestingT, h *mockitmetrics.Histogram, want int, labelValues ...string) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+
+	stats, ok := h.Stats(labelValues...)
+	if !ok {
+		if want == 0 {
+			return true
+		}
+		return fail(t, h, "no histogram series %s", selector(labelValues))
+	}
+	if stats.Count != uint64(want) {
+		return fail(t, h, "histogram series %s: want %d observations, got %d",
+			selector(labelValues), want, stats.Count)
+	}
+
+	return true
+}
+
+// HistogramContains asserts that the histogram series with exactly the provided
+// label name and value pairs has observed the value at least once.  Only the
+// retained observations are checked, so this is only reliable with the
+// default RetainAll storage.
+func HistogramContains(t assert.TestingT, h *mockitmetrics.Histogram, value float64, labelValues ...string) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+
+	got, ok := h.Get(labelValues...)
+	if !ok {
+		return fail(t, h, "no histogram series %s", selector(labelValues))
+	}
+	for _, v := range got {
+		if v == value {
+			return true
+		}
+	}
+
+	return fail(t, h, "histogram series %s: %s was not observed",
+		selector(labelValues), formatFloat(value))
+}
+
+// NoSamples asserts that the metric has not recorded any series.  The metric
+// must be a *mockitmetrics.Counter, *mockitmetrics.Gauge or
+// *mockitmetrics.Histogram.
+func NoSamples(t assert.TestingT, metric any) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+
+	lines, err := series(metric)
+	if err != nil {
+		return assert.Fail(t, err.Error())
+	}
+	if len(lines) != 0 {
+		return fail(t, metric, "expected no series, got %d", len(lines))
+	}
+
+	return true
+}
+
+// OnlyLabelSets asserts that the metric has recorded exactly the provided
+// series, and no others.  Each set is a list of label name and value pairs, in
+// any order.  The metric must be a *mockitmetrics.Counter,
+// *mockitmetrics.Gauge or *mockitmetrics.Histogram.
+func OnlyLabelSets(t assert.TestingT, metric any, sets ...[]string) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+
+	got, err := labelSets(metric)
+	if err != nil {
+		return assert.Fail(t, err.Error())
+	}
+
+	want := make([]string, 0, len(sets))
+	for _, set := range sets {
+		if len(set)%2 != 0 {
+			return assert.Fail(t, fmt.Sprintf("label set %q must be label and value pairs", set))
+		}
+		want = append(want, selector(set))
+	}
+	sort.Strings(want)
+
+	missing := difference(want, got)
+	extra := difference(got, want)
+	if len(missing) == 0 && len(extra) == 0 {
+		return true
+	}
+
+	var b strings.Builder
+	b.WriteString("label sets do not match")
+	for _, m := range missing {
+		b.WriteString("\n  missing:    " + m)
+	}
+	for _, e := range extra {
+		b.WriteString("\n  unexpected: " + e)
+	}
+
+	return fail(t, metric, "%s", b.String())
+}
+
+// fail reports the message along with a listing of all the series in the
+// metric.
+func fail(t assert.TestingT, metric any, format string, args ...any) bool {
+	if th, ok := t.(tHelper); ok {
+		th.Helper()
+	}
+
+	msg := fmt.Sprintf(format, args...)
+
+	lines, _ := series(metric)
+	if len(lines) == 0 {
+		return assert.Fail(t, msg+"\nseries: (none)")
+	}
+
+	return assert.Fail(t, msg+"\nseries:\n  "+strings.Join(lines, "\n  "))
+}
+
+// series returns a line per series of the metric, sorted by label set.
+func series(metric any) ([]string, error) {
+	var rv []string
+	switch m := metric.(type) {
+	case *mockitmetrics.Counter:
+		for _, s := range m.Samples() {
+			rv = append(rv, format(s.Labels)+" "+formatFloat(s.Value))
+		}
+	case *mockitmetrics.Gauge:
+		for _, s := range m.Samples() {
+			rv = append(rv, format(s.Labels)+" "+formatFloat(s.Value))
+		}
+	case *mockitmetrics.Histogram:
+		for _, s := range m.Samples() {
+			values := make([]string, 0, len(s.Values))
+			for _, v := range s.Values {
+				values = append(values, formatFloat(v))
+			}
+			rv = append(rv, format(s.Labels)+" ["+strings.Join(values, " ")+"]")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported metric type %T", metric)
+	}
+
+	sort.Strings(rv)
+	return rv, nil
+}
+
+// labelSets returns the sorted label set of each series of the metric.
+func labelSets(metric any) ([]string, error) {
+	var rv []string
+	switch m := metric.(type) {
+	case *mockitmetrics.Counter:
+		for _, s := range m.Samples() {
+			rv = append(rv, format(s.Labels))
+		}
+	case *mockitmetrics.Gauge:
+		for _, s := range m.Samples() {
+			rv = append(rv, format(s.Labels))
+		}
+	case *mockitmetrics.Histogram:
+		for _, s := range m.Samples() {
+			rv = append(rv, format(s.Labels))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported metric type %T", metric)
+	}
+
+	sort.Strings(rv)
+	return rv, nil
+}
+
+// selector formats label name and value pairs the same way format does.
+func selector(labelValues []string) string {
+	labels := make([]mockitmetrics.Label, 0, len(labelValues)/2)
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		labels = append(labels, mockitmetrics.Label{
+			Name:  labelValues[i],
+			Value: labelValues[i+1],
+		})
+	}
+	return format(labels)
+}
+
+// format renders the labels sorted by name, like {code="500", method="GET"}.
+func format(labels []mockitmetrics.Label) string {
+	pairs := make([]string, 0, len(labels))
+	for _, l := range labels {
+		pairs = append(pairs, l.Name+"="+strconv.Quote(l.Value))
+	}
+	sort.Strings(pairs)
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// difference returns the entries of a not found in b.  Both must be sorted.
+func difference(a, b []string) []string {
+	var rv []string
+	for _, s := range a {
+		i := sort.SearchStrings(b, s)
+		if i == len(b) || b[i] != s {
+			rv = append(rv, s)
+		}
+	}
+	return rv
+}
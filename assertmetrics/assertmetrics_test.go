@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package assertmetrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/mockitmetrics"
+)
+
+type mockT struct {
+	errors []string
+}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	c := mockitmetrics.NewCounter()
+	c.With("method", "GET", "code", "200").Add(3)
+	c.With("method", "GET", "code", "500").Add(1)
+
+	g := mockitmetrics.NewGauge()
+	g.With("queue", "a").Set(5)
+
+	h := mockitmetrics.NewHistogram()
+	h.With("route", "/x").Observe(0.5)
+	h.With("route", "/x").Observe(1.5)
+
+	tests := []struct {
+		description string
+		fn          func(assert.TestingT) bool
+		contains    []string
+	}{
+		{
+			description: "counter equals",
+			fn: func(t assert.TestingT) bool {
+				return CounterEquals(t, c, 3, "code", "200", "method", "GET")
+			},
+		}, {
+			description: "counter has a different value",
+			fn: func(t assert.TestingT) bool {
+				return CounterEquals(t, c, 2, "code", "500", "method", "GET")
+			},
+			contains: []string{
+				`counter series {code="500", method="GET"}: want 2, got 1`,
+				`{code="200", method="GET"} 3`,
+				`{code="500", method="GET"} 1`,
+			},
+		}, {
+			description: "counter series is missing",
+			fn: func(t assert.TestingT) bool {
+				return CounterEquals(t, c, 1, "code", "404", "method", "GET")
+			},
+			contains: []string{`no counter series {code="404", method="GET"}`},
+		}, {
+			description: "gauge equals",
+			fn: func(t assert.TestingT) bool {
+				return GaugeEquals(t, g, 5, "queue", "a")
+			},
+		}, {
+			description: "gauge has a different value",
+			fn: func(t assert.TestingT) bool {
+				return GaugeEquals(t, g, 4, "queue", "a")
+			},
+			contains: []string{`gauge series {queue="a"}: want 4, got 5`},
+		}, {
+			description: "histogram count",
+			fn: func(t assert.TestingT) bool {
+				return HistogramCount(t, h, 2, "route", "/x")
+			},
+		}, {
+			description: "histogram count of a missing series is zero",
+			fn: func(t assert.TestingT) bool {
+				return HistogramCount(t, h, 0, "route", "/y")
+			},
+		}, {
+			description: "histogram count is different",
+			fn: func(t assert.TestingT) bool {
+				return HistogramCount(t, h, 3, "route", "/x")
+			},
+			contains: []string{
+				`histogram series {route="/x"}: want 3 observations, got 2`,
+				`{route="/x"} [0.5 1.5]`,
+			},
+		}, {
+			description: "histogram contains",
+			fn: func(t assert.TestingT) bool {
+				return HistogramContains(t, h, 1.5, "route", "/x")
+			},
+		}, {
+			description: "histogram does not contain",
+			fn: func(t assert.TestingT) bool {
+				return HistogramContains(t, h, 2, "route", "/x")
+			},
+			contains: []string{`histogram series {route="/x"}: 2 was not observed`},
+		}, {
+			description: "no samples",
+			fn: func(t assert.TestingT) bool {
+				return NoSamples(t, mockitmetrics.NewGauge())
+			},
+		}, {
+			description: "samples when none are expected",
+			fn: func(t assert.TestingT) bool {
+				return NoSamples(t, g)
+			},
+			contains: []string{"expected no series, got 1", `{queue="a"} 5`},
+		}, {
+			description: "unsupported metric",
+			fn: func(t assert.TestingT) bool {
+				return NoSamples(t, "counter")
+			},
+			contains: []string{"unsupported metric type string"},
+		}, {
+			description: "only label sets",
+			fn: func(t assert.TestingT) bool {
+				return OnlyLabelSets(t, c,
+					[]string{"code", "500", "method", "GET"},
+					[]string{"method", "GET", "code", "200"},
+				)
+			},
+		}, {
+			description: "label sets do not match",
+			fn: func(t assert.TestingT) bool {
+				return OnlyLabelSets(t, c,
+					[]string{"code", "200", "method", "GET"},
+					[]string{"code", "404", "method", "GET"},
+				)
+			},
+			contains: []string{
+				`missing:    {code="404", method="GET"}`,
+				`unexpected: {code="500", method="GET"}`,
+			},
+		}, {
+			description: "label sets must be pairs",
+			fn: func(t assert.TestingT) bool {
+				return OnlyLabelSets(t, h, []string{"route"})
+			},
+			contains: []string{"must be label and value pairs"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			m := &mockT{}
+			ok := tc.fn(m)
+
+			if len(tc.contains) == 0 {
+				assert.True(ok)
+				assert.Empty(m.errors)
+				return
+			}
+
+			assert.False(ok)
+			if assert.Len(m.errors, 1) {
+				for _, s := range tc.contains {
+					assert.Contains(m.errors[0], s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	errTooManySeries = errors.New("too many series")
+)
+
+// LabelCardinality describes the values seen for a single label name.
+type LabelCardinality struct {
+	// Name is the label name.
+	Name string
+
+	// Distinct is the number of distinct values seen for the label.
+	Distinct int
+
+	// Values are the values seen for the label, along with the number of
+	// series using each one, ordered from the most used to the least used.
+	Values []ValueCount
+}
+
+// ValueCount is the number of series using a label value.
+type ValueCount struct {
+	Value  string
+	Series int
+}
+
+// checkSeries returns an error if adding another series would exceed the
+// maximum number of series.  A maximum of zero means there is no limit.
+func checkSeries(max, current int, lvp []tuple) error {
+	if max <= 0 || current < max {
+		return nil
+	}
+
+	return fmt.Errorf("%w - adding series %s would exceed the limit of %d series",
+		errTooManySeries, formatLabels(lvp), max)
+}
+
+// cardinality returns the cardinality of each label name, ordered from the
+// most distinct values to the least.
+func cardinality(labels map[string][]tuple) []LabelCardinality {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	counts := map[string]map[string]int{}
+	for _, lvp := range labels {
+		for _, t := range lvp {
+			if counts[t.label] == nil {
+				counts[t.label] = map[string]int{}
+			}
+			counts[t.label][t.value]++
+		}
+	}
+
+	rv := make([]LabelCardinality, 0, len(counts))
+	for name, values := range counts {
+		lc := LabelCardinality{
+			Name:     name,
+			Distinct: len(values),
+			Values:   make([]ValueCount, 0, len(values)),
+		}
+		for v, n := range values {
+			lc.Values = append(lc.Values, ValueCount{Value: v, Series: n})
+		}
+		sort.Slice(lc.Values, func(i, j int) bool {
+			if lc.Values[i].Series != lc.Values[j].Series {
+				return lc.Values[i].Series > lc.Values[j].Series
+			}
+			return lc.Values[i].Value < lc.Values[j].Value
+		})
+		rv = append(rv, lc)
+	}
+
+	sort.Slice(rv, func(i, j int) bool {
+		if rv[i].Distinct != rv[j].Distinct {
+			return rv[i].Distinct > rv[j].Distinct
+		}
+		return rv[i].Name < rv[j].Name
+	})
+
+	return rv
+}
+
+// Cardinality returns the cardinality of each label name used by the tree of
+// counters, ordered from the most distinct values to the least.
+func (c *Counter) Cardinality() []LabelCardinality {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return cardinality(root.labels)
+}
+
+// Cardinality returns the cardinality of each label name used by the gauge,
+// ordered from the most distinct values to the least.
+func (g *Gauge) Cardinality() []LabelCardinality {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return cardinality(root.labels)
+}
+
+// Cardinality returns the cardinality of each label name used by the
+// histogram, ordered from the most distinct values to the least.
+func (h *Histogram) Cardinality() []LabelCardinality {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return cardinality(root.labels)
+}
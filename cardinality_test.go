@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxSeries(t *testing.T) {
+	tests := []struct {
+		description string
+		fn          func(opts ...Option)
+	}{
+		{
+			description: "counter",
+			fn: func(opts ...Option) {
+				c := NewCounter(opts...)
+				c.With("user", "1").Add(1)
+				c.With("user", "2").Add(1)
+				c.With("user", "1").Add(1)
+				c.With("user", "3").Add(1)
+			},
+		}, {
+			description: "gauge",
+			fn: func(opts ...Option) {
+				g := NewGauge(opts...)
+				g.With("user", "1").Set(1)
+				g.With("user", "2").Set(1)
+				g.With("user", "1").Add(1)
+				g.With("user", "3").Set(1)
+			},
+		}, {
+			description: "histogram",
+			fn: func(opts ...Option) {
+				h := NewHistogram(opts...)
+				h.With("user", "1").Observe(1)
+				h.With("user", "2").Observe(1)
+				h.With("user", "1").Observe(1)
+				h.With("user", "3").Observe(1)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.NotPanics(func() { tc.fn() })
+			assert.NotPanics(func() { tc.fn(MaxSeries(3)) })
+			assert.Panics(func() { tc.fn(MaxSeries(2)) })
+
+			tb := &fakeTB{TB: t}
+			tc.fn(WithT(tb), MaxSeries(2))
+			assert.Equal([]string{
+				`too many series - adding series {user="3"} would exceed the limit of 2 series`,
+			}, tb.Errors())
+		})
+	}
+}
+
+func TestCardinality(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewCounter()
+	assert.Nil(c.Cardinality())
+
+	c.With("method", "GET", "path", "/a").Add(1)
+	c.With("method", "GET", "path", "/b").Add(1)
+	c.With("method", "PUT", "path", "/c").Add(1)
+	c.With("method", "GET", "path", "/c").Add(1)
+
+	expected := []LabelCardinality{
+		{
+			Name:     "path",
+			Distinct: 3,
+			Values: []ValueCount{
+				{Value: "/c", Series: 2},
+				{Value: "/a", Series: 1},
+				{Value: "/b", Series: 1},
+			},
+		}, {
+			Name:     "method",
+			Distinct: 2,
+			Values: []ValueCount{
+				{Value: "GET", Series: 3},
+				{Value: "PUT", Series: 1},
+			},
+		},
+	}
+	assert.Equal(expected, c.Cardinality())
+
+	g := NewGauge()
+	h := NewHistogram()
+	for _, lv := range [][]string{
+		{"method", "GET", "path", "/a"},
+		{"method", "GET", "path", "/b"},
+		{"method", "PUT", "path", "/c"},
+		{"method", "GET", "path", "/c"},
+	} {
+		g.With(lv...).Set(1)
+		h.With(lv...).Observe(1)
+	}
+	assert.Equal(expected, g.Cardinality())
+	assert.Equal(expected, h.Cardinality())
+}
@@ -5,6 +5,8 @@ package mockitmetrics
 
 import (
 	"sync"
+	"sync/atomic"
+	"testing"
 
 	kit "github.com/go-kit/kit/metrics"
 )
@@ -21,19 +23,47 @@ func NewCounter(opts ...Option) *Counter {
 			opt.counterApply(&c)
 		}
 	}
+	c.invalid = validateLabels(c.expectedLabels, nil, true)
+
+	if c.t != nil {
+		c.t.Helper()
+	}
+	if err := validateName(c.meta.Name); err != nil {
+		c.panic(err)
+	}
 
 	return &c
 }
 
 // Counter is a mock counter.
 type Counter struct {
-	value          map[string]float64
-	panic          func(any)
-	delimiter      string
-	m              sync.Mutex
-	root           *Counter
-	expectedLabels *[]string
-	lvp            []tuple
+	value           map[string]*counterSeries
+	labels          map[string][]tuple
+	index           sync.Map
+	panic           func(any)
+	meta            Metadata
+	delimiter       string
+	m               sync.Mutex
+	root            *Counter
+	expectedLabels  *[]string
+	lvp             []tuple
+	key             string
+	invalid         error
+	series          atomic.Pointer[counterSeries]
+	rejectDelimiter bool
+	strict          bool
+	anyLabelOrder   bool
+	duplicates      DuplicatePolicy
+	valueRules      map[string]*valueRule
+	maxSeries       int
+	t               testing.TB
+	discard         bool
+	resets          uint64
+	expectations    []expectation
+	events          eventLog
+	changed         notifier
+	listeners       listeners
+	spy             kit.Counter
 }
 
 var _ kit.Counter = (*Counter)(nil)
@@ -45,26 +75,77 @@ func (c *Counter) With(labelValues ...string) kit.Counter {
 		root = c.root
 	}
 
+	if root.t != nil {
+		root.t.Helper()
+	}
+
+	var spy kit.Counter
+	if c.spy != nil {
+		spy = c.spy.With(labelValues...)
+	}
+
+	if c.discard {
+		return &Counter{
+			root:    root,
+			discard: true,
+			spy:     spy,
+		}
+	}
+
 	lvp, err := convert(labelValues)
 	if err != nil {
 		goto failure
 	}
 
-	lvp = append(c.lvp, lvp...)
+	lvp, err = mergeLabels(c.lvp, lvp, root.duplicates)
+	if err != nil {
+		goto failure
+	}
 
-	err = validateLabels(root.expectedLabels, lvp, false)
+	if root.anyLabelOrder {
+		lvp = orderLabels(root.expectedLabels, lvp)
+		err = validateLabelSet(root.expectedLabels, lvp)
+	} else {
+		err = validateLabels(root.expectedLabels, lvp, false)
+	}
+	if err != nil {
+		goto failure
+	}
+
+	if root.rejectDelimiter {
+		err = checkDelimiter(lvp, root.delimiter)
+		if err != nil {
+			goto failure
+		}
+	}
+
+	if root.strict {
+		err = checkPrometheus(lvp)
+		if err != nil {
+			goto failure
+		}
+	}
+
+	err = checkValues(root.valueRules, lvp)
 	if err != nil {
 		goto failure
 	}
 
 	return &Counter{
-		root: root,
-		lvp:  lvp,
+		root:    root,
+		lvp:     lvp,
+		key:     seriesKey(lvp),
+		invalid: validateLabels(root.expectedLabels, lvp, true),
+		spy:     spy,
 	}
 
 failure:
-	root.panic(err)
-	return nil
+	root.panic(root.meta.wrap(err))
+	return &Counter{
+		root:    root,
+		discard: true,
+		spy:     spy,
+	}
 }
 
 // Add adds the provided delta to the counter.
@@ -74,32 +155,93 @@ func (c *Counter) Add(delta float64) {
 		root = c
 	}
 
+	if root.t != nil {
+		root.t.Helper()
+	}
+
+	if c.spy != nil {
+		c.spy.Add(delta)
+	}
+
+	if c.discard {
+		return
+	}
+
 	if delta < 0.0 {
 		root.panic("delta must be non-negative")
 		return
 	}
 
-	if err := validateLabels(root.expectedLabels, c.lvp, true); err != nil {
-		root.panic(err)
+	if c.invalid != nil {
+		root.panic(root.meta.wrap(c.invalid))
+		return
+	}
+
+	if c.fastAdd(root, delta) {
 		return
 	}
 
-	label := joinValues(c.lvp, root.delimiter)
+	caller := root.events.caller()
 
 	root.m.Lock()
-	defer root.m.Unlock()
 
 	if root.value == nil {
-		root.value = map[string]float64{}
+		root.value = map[string]*counterSeries{}
+		root.labels = map[string][]tuple{}
 	}
 
-	if _, ok := root.value[label]; !ok {
-		root.value[label] = 0.0
+	s, ok := root.value[c.key]
+	if !ok {
+		if err := checkSeries(root.maxSeries, len(root.value), c.lvp); err != nil {
+			root.m.Unlock()
+			root.panic(root.meta.wrap(err))
+			return
+		}
+		s = &counterSeries{}
+		root.value[c.key] = s
+		root.labels[c.key] = c.lvp
+		root.index.Store(c.key, s)
 	}
-	root.value[label] += delta
+	value, _ := s.add(delta)
+	c.series.Store(s)
+	root.events.add(KindCounter, OpAdd, c.lvp, delta, caller)
+	root.changed.notify()
+	publish := root.listeners.prepare(KindCounter, OpAdd, c.lvp, delta, value)
+	root.m.Unlock()
+
+	publish()
+}
+
+// fastAdd adds the delta to the series found by an earlier call to Add without
+// taking the root lock.  It returns false if the root lock is needed.
+func (c *Counter) fastAdd(root *Counter, delta float64) bool {
+	if root.events.record || !root.listeners.empty() {
+		return false
+	}
+
+	s := c.series.Load()
+	if s == nil {
+		found, ok := root.index.Load(c.key)
+		if !ok {
+			return false
+		}
+		s = found.(*counterSeries)
+		c.series.Store(s)
+	}
+
+	if _, ok := s.add(delta); !ok {
+		return false
+	}
+
+	root.changed.notify()
+	return true
 }
 
 // Value returns the current value of the tree of counters.
+//
+// The keys are the label values joined by the delimiter, with any delimiter in
+// a value escaped by a backslash.  Series that differ only by label names
+// share a key and are summed; use Samples() to tell them apart.
 func (c *Counter) Value() map[string]float64 {
 	root := c.root
 	if root == nil {
@@ -115,8 +257,142 @@ func (c *Counter) Value() map[string]float64 {
 
 	rv := map[string]float64{}
 
-	for k, v := range root.value {
-		rv[k] = v
+	for k, s := range root.value {
+		v, _ := s.get()
+		rv[joinValues(root.labels[k], root.delimiter)] += v
+	}
+	return rv
+}
+
+// Samples returns the current value of each series in the tree of counters.
+func (c *Counter) Samples() []Sample {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	if len(root.value) == 0 {
+		return nil
+	}
+
+	rv := make([]Sample, 0, len(root.value))
+	for k, s := range root.value {
+		v, _ := s.get()
+		rv = append(rv, Sample{
+			Labels: toLabels(root.labels[k]),
+			Value:  v,
+		})
 	}
+	sortSamples(rv)
+
 	return rv
 }
+
+// Get returns the value of the series with exactly the provided label name
+// and value pairs, in any order.  If no series matches, false is returned.
+// If the labels were passed to With in more than one order, they match more
+// than one series; the ambiguity is reported and false is returned.
+func (c *Counter) Get(labelValues ...string) (float64, bool) {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	s, err := c.lookup(labelValues)
+	if err != nil {
+		root.panic(root.meta.wrap(err))
+		return 0, false
+	}
+	if s == nil {
+		return 0, false
+	}
+
+	v, _ := s.get()
+	return v, true
+}
+
+// lookup returns the series with exactly the provided label name and value
+// pairs, in any order, or nil if no series matches.
+func (c *Counter) lookup(labelValues []string) (*counterSeries, error) {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	want, err := convert(labelValues)
+	if err != nil {
+		return nil, nil
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	k, ok, err := findLabels(root.labels, want)
+	if !ok {
+		return nil, err
+	}
+
+	return root.value[k], nil
+}
+
+// Events returns the events recorded by the tree of counters.  Events are only
+// recorded when RecordEvents or RecordCallers is used.
+func (c *Counter) Events() []Event {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return root.events.events()
+}
+
+// Snapshot returns an immutable copy of all of the series of the counter.
+func (c *Counter) Snapshot() *Snapshot {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	series := make([]snapshotSeries, 0, len(root.value))
+	for k, s := range root.value {
+		v, _ := s.get()
+		series = append(series, snapshotSeries{
+			key:    k,
+			labels: root.labels[k],
+			value:  v,
+		})
+	}
+
+	return newSnapshot(KindCounter, root.resets, series)
+}
+
+// Reset discards all of the series and events recorded by the tree of
+// counters.  Options and expectations are kept.
+func (c *Counter) Reset() {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	for k, s := range root.value {
+		s.discard()
+		root.index.Delete(k)
+	}
+	root.value = nil
+	root.labels = nil
+	root.resets++
+	root.events.list = nil
+	root.changed.notify()
+}
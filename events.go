@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Kind is the kind of metric.
+type Kind int
+
+const (
+	KindCounter Kind = iota + 1
+	KindGauge
+	KindHistogram
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindCounter:
+		return "counter"
+	case KindGauge:
+		return "gauge"
+	case KindHistogram:
+		return "histogram"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Op is the operation performed on a metric.
+type Op string
+
+const (
+	OpAdd     Op = "Add"
+	OpSet     Op = "Set"
+	OpObserve Op = "Observe"
+)
+
+// Event is a single update of a metric.
+type Event struct {
+	// Seq is the order of the event.  It increases monotonically across all
+	// metrics in the process, so events from different metrics can be ordered
+	// relative to each other.
+	Seq uint64
+
+	// Kind is the kind of metric that was updated.
+	Kind Kind
+
+	// Op is the operation that was performed.
+	Op Op
+
+	// Labels are the label name and value pairs of the series.
+	Labels []Label
+
+	// Arg is the value passed to Add, Set or Observe.
+	Arg float64
+
+	// Time is when the event happened, as reported by the clock set using
+	// TimeFunc.
+	Time time.Time
+
+	// Caller is the file:line that performed the operation.  It is only
+	// populated when RecordCallers is used.
+	Caller string
+}
+
+// eventSeq is shared by all metrics so events can be ordered across them.
+var eventSeq atomic.Uint64
+
+// eventLog is the list of events recorded by the root of a metric tree.
+type eventLog struct {
+	record  bool
+	callers bool
+	now     func() time.Time
+	list    []Event
+}
+
+// add records the event if recording is enabled.  The root lock must be held
+// by the caller.
+func (l *eventLog) add(kind Kind, op Op, lvp []tuple, arg float64, caller string) {
+	if !l.record {
+		return
+	}
+
+	now := time.Now
+	if l.now != nil {
+		now = l.now
+	}
+
+	l.list = append(l.list, Event{
+		Seq:    eventSeq.Add(1),
+		Kind:   kind,
+		Op:     op,
+		Labels: toLabels(lvp),
+		Arg:    arg,
+		Time:   now(),
+		Caller: caller,
+	})
+}
+
+// caller returns the file:line of the caller if callers are being recorded.
+// This is separate from add so the (relatively) expensive stack walk can be
+// done before the root lock is taken.
+func (l *eventLog) caller() string {
+	if !l.callers {
+		return ""
+	}
+	return caller()
+}
+
+func (l *eventLog) events() []Event {
+	if len(l.list) == 0 {
+		return nil
+	}
+	return append([]Event(nil), l.list...)
+}
+
+// pkgDir is the directory of this package, used to skip over its frames when
+// looking for the caller.
+var pkgDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// caller returns the file:line of the first frame outside of this package and
+// the go-kit metrics package, which wraps metrics in things like timers.
+func caller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		f, more := frames.Next()
+
+		inPkg := filepath.Dir(f.File) == pkgDir && !strings.HasSuffix(f.File, "_test.go")
+		inKit := strings.HasPrefix(f.Function, "github.com/go-kit/kit/metrics.")
+		if !inPkg && !inKit {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
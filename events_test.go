@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	c := NewCounter(RecordEvents(), TimeFunc(clock))
+	g := NewGauge(RecordEvents(), TimeFunc(clock))
+	h := NewHistogram(RecordCallers(), TimeFunc(clock))
+
+	c.With("code", "200").Add(1)
+	g.Set(10)
+	g.Add(-10)
+	h.With("route", "/x").Observe(0.5)
+	c.With("code", "200").Add(2)
+
+	ce := c.With("code", "200").(*Counter).Events()
+	ge := g.Events()
+	he := h.Events()
+	require.Len(ce, 2)
+	require.Len(ge, 2)
+	require.Len(he, 1)
+
+	assert.Equal(Event{
+		Seq:    ce[0].Seq,
+		Kind:   KindCounter,
+		Op:     OpAdd,
+		Labels: []Label{{Name: "code", Value: "200"}},
+		Arg:    1,
+		Time:   start.Add(1 * time.Second),
+	}, ce[0])
+	assert.Equal(Event{
+		Seq:    ge[0].Seq,
+		Kind:   KindGauge,
+		Op:     OpSet,
+		Labels: []Label{},
+		Arg:    10,
+		Time:   start.Add(2 * time.Second),
+	}, ge[0])
+	assert.Equal(OpAdd, ge[1].Op)
+	assert.Equal(-10.0, ge[1].Arg)
+	assert.Equal(KindHistogram, he[0].Kind)
+	assert.Equal(OpObserve, he[0].Op)
+	assert.Equal(start.Add(4*time.Second), he[0].Time)
+	assert.Equal(2.0, ce[1].Arg)
+
+	// The sequence orders the events across the metrics.
+	assert.Less(ce[0].Seq, ge[0].Seq)
+	assert.Less(ge[0].Seq, ge[1].Seq)
+	assert.Less(ge[1].Seq, he[0].Seq)
+	assert.Less(he[0].Seq, ce[1].Seq)
+
+	// Only the histogram records callers.
+	assert.Empty(ce[0].Caller)
+	assert.True(strings.Contains(he[0].Caller, "events_test.go:"), he[0].Caller)
+}
+
+func TestEventsNotRecorded(t *testing.T) {
+	c := NewCounter()
+	c.Add(1)
+
+	assert.Nil(t, c.Events())
+	assert.Equal(t, "counter", KindCounter.String())
+	assert.Equal(t, "gauge", KindGauge.String())
+	assert.Equal(t, "histogram", KindHistogram.String())
+	assert.Equal(t, "Kind(0)", Kind(0).String())
+}
@@ -0,0 +1,357 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errExpectation = errors.New("expectation not met")
+)
+
+// expectation checks the state of a metric, returning an error if it doesn't
+// match what was expected.  It is called with the root lock held.
+type expectation func() error
+
+// verify runs all of the expectations and returns an error for each one that
+// isn't met.
+func verify(m Metadata, expectations []expectation) []error {
+	var rv []error
+	for _, e := range expectations {
+		if err := e(); err != nil {
+			rv = append(rv, m.wrap(err))
+		}
+	}
+	return rv
+}
+
+// combine turns the list of errors into a single error.
+func combine(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+
+	list := make([]string, 0, len(errs))
+	for _, err := range errs {
+		list = append(list, err.Error())
+	}
+
+	return fmt.Errorf("%w - %d expectations not met:\n%s",
+		errExpectation, len(errs), strings.Join(list, "\n"))
+}
+
+// CounterExpectation describes the expected use of a counter.  It applies to
+// every series of the counter unless narrowed using With.
+type CounterExpectation struct {
+	root   *Counter
+	labels []tuple
+	err    error
+}
+
+// Expect starts a new expectation for the counter.  Expectations are verified
+// when the test completes if WithT was provided, or by calling Verify.
+func (c *Counter) Expect() *CounterExpectation {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	return &CounterExpectation{root: root}
+}
+
+// With narrows the expectation to the series that have all of the provided
+// label name and value pairs.
+func (e *CounterExpectation) With(labelValues ...string) *CounterExpectation {
+	lvp, err := convert(labelValues)
+	if e.err != nil {
+		err = e.err
+	}
+
+	return &CounterExpectation{
+		root:   e.root,
+		labels: append(append([]tuple{}, e.labels...), lvp...),
+		err:    err,
+	}
+}
+
+// AddedTimes expects Add to have been called exactly n times on the matching
+// series.
+func (e *CounterExpectation) AddedTimes(n int) {
+	e.expect(func() error {
+		got := 0
+		for k, lvp := range e.root.labels {
+			if containsLabels(lvp, e.labels) {
+				_, adds := e.root.value[k].get()
+				got += adds
+			}
+		}
+
+		if got != n {
+			return fmt.Errorf("%w - counter %s: want %d calls to Add, got %d",
+				errExpectation, formatLabels(e.labels), n, got)
+		}
+		return nil
+	})
+}
+
+// Never expects the matching series to never have been updated.
+func (e *CounterExpectation) Never() {
+	e.AddedTimes(0)
+}
+
+func (e *CounterExpectation) expect(fn expectation) {
+	if err := e.err; err != nil {
+		fn = func() error { return err }
+	}
+
+	e.root.m.Lock()
+	defer e.root.m.Unlock()
+
+	e.root.expectations = append(e.root.expectations, fn)
+}
+
+// Verify checks all of the expectations of the counter, returning an error
+// describing each one that isn't met.
+func (c *Counter) Verify() error {
+	return combine(c.verify())
+}
+
+func (c *Counter) verify() []error {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return verify(root.meta, root.expectations)
+}
+
+// GaugeExpectation describes the expected use of a gauge.  It applies to every
+// series of the gauge unless narrowed using With.
+type GaugeExpectation struct {
+	root   *Gauge
+	labels []tuple
+	err    error
+}
+
+// Expect starts a new expectation for the gauge.  Expectations are verified
+// when the test completes if WithT was provided, or by calling Verify.
+func (g *Gauge) Expect() *GaugeExpectation {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	return &GaugeExpectation{root: root}
+}
+
+// With narrows the expectation to the series that have all of the provided
+// label name and value pairs.
+func (e *GaugeExpectation) With(labelValues ...string) *GaugeExpectation {
+	lvp, err := convert(labelValues)
+	if e.err != nil {
+		err = e.err
+	}
+
+	return &GaugeExpectation{
+		root:   e.root,
+		labels: append(append([]tuple{}, e.labels...), lvp...),
+		err:    err,
+	}
+}
+
+// FinalValue expects every matching series to end with the provided value.
+// At least one series must match.
+func (e *GaugeExpectation) FinalValue(value float64) {
+	e.expect(func() error {
+		found := false
+		for k, lvp := range e.root.labels {
+			if !containsLabels(lvp, e.labels) {
+				continue
+			}
+
+			found = true
+			if got := e.root.value[k].get(); got != value {
+				return fmt.Errorf("%w - gauge %s: want a final value of %g, got %g for %s",
+					errExpectation, formatLabels(e.labels), value, got, formatLabels(lvp))
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("%w - gauge %s: want a final value of %g, but it was never updated",
+				errExpectation, formatLabels(e.labels), value)
+		}
+		return nil
+	})
+}
+
+// Never expects the matching series to never have been updated.
+func (e *GaugeExpectation) Never() {
+	e.expect(func() error {
+		for k, lvp := range e.root.labels {
+			if containsLabels(lvp, e.labels) {
+				return fmt.Errorf("%w - gauge %s: want no updates, got %g for %s",
+					errExpectation, formatLabels(e.labels), e.root.value[k].get(), formatLabels(lvp))
+			}
+		}
+		return nil
+	})
+}
+
+func (e *GaugeExpectation) expect(fn expectation) {
+	if err := e.err; err != nil {
+		fn = func() error { return err }
+	}
+
+	e.root.m.Lock()
+	defer e.root.m.Unlock()
+
+	e.root.expectations = append(e.root.expectations, fn)
+}
+
+// Verify checks all of the expectations of the gauge, returning an error
+// describing each one that isn't met.
+func (g *Gauge) Verify() error {
+	return combine(g.verify())
+}
+
+func (g *Gauge) verify() []error {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return verify(root.meta, root.expectations)
+}
+
+// HistogramExpectation describes the expected use of a histogram.  It applies
+// to every series of the histogram unless narrowed using With.
+type HistogramExpectation struct {
+	root   *Histogram
+	labels []tuple
+	err    error
+}
+
+// Expect starts a new expectation for the histogram.  Expectations are
+// verified when the test completes if WithT was provided, or by calling
+// Verify.
+func (h *Histogram) Expect() *HistogramExpectation {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	return &HistogramExpectation{root: root}
+}
+
+// With narrows the expectation to the series that have all of the provided
+// label name and value pairs.
+func (e *HistogramExpectation) With(labelValues ...string) *HistogramExpectation {
+	lvp, err := convert(labelValues)
+	if e.err != nil {
+		err = e.err
+	}
+
+	return &HistogramExpectation{
+		root:   e.root,
+		labels: append(append([]tuple{}, e.labels...), lvp...),
+		err:    err,
+	}
+}
+
+// ObservedTimes expects exactly n observations across the matching series.
+func (e *HistogramExpectation) ObservedTimes(n int) {
+	e.expect(func() error {
+		got := 0
+		for k, lvp := range e.root.labels {
+			if containsLabels(lvp, e.labels) {
+				e.root.value[k].read(func(o *observations) {
+					got += int(o.count)
+				})
+			}
+		}
+
+		if got != n {
+			return fmt.Errorf("%w - histogram %s: want %d observations, got %d",
+				errExpectation, formatLabels(e.labels), n, got)
+		}
+		return nil
+	})
+}
+
+// ObservedBetween expects at least one observation across the matching series,
+// and every observation to be within min and max, inclusive.
+func (e *HistogramExpectation) ObservedBetween(min, max float64) {
+	e.expect(func() error {
+		found := false
+		for k, lvp := range e.root.labels {
+			if !containsLabels(lvp, e.labels) {
+				continue
+			}
+
+			var bounds []float64
+			e.root.value[k].read(func(o *observations) {
+				bounds = []float64{o.min, o.max}
+			})
+			found = true
+			for _, v := range bounds {
+				if v < min || max < v {
+					return fmt.Errorf("%w - histogram %s: want observations between %g and %g, got %g for %s",
+						errExpectation, formatLabels(e.labels), min, max, v, formatLabels(lvp))
+				}
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("%w - histogram %s: want observations between %g and %g, got none",
+				errExpectation, formatLabels(e.labels), min, max)
+		}
+		return nil
+	})
+}
+
+// Never expects the matching series to never have been observed.
+func (e *HistogramExpectation) Never() {
+	e.ObservedTimes(0)
+}
+
+func (e *HistogramExpectation) expect(fn expectation) {
+	if err := e.err; err != nil {
+		fn = func() error { return err }
+	}
+
+	e.root.m.Lock()
+	defer e.root.m.Unlock()
+
+	e.root.expectations = append(e.root.expectations, fn)
+}
+
+// Verify checks all of the expectations of the histogram, returning an error
+// describing each one that isn't met.
+func (h *Histogram) Verify() error {
+	return combine(h.verify())
+}
+
+func (h *Histogram) verify() []error {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return verify(root.meta, root.expectations)
+}
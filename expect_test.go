@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpectations(t *testing.T) {
+	tests := []struct {
+		description string
+		fn          func(opts ...Option) verifier
+		errors      []string
+	}{
+		{
+			description: "counter added the expected number of times",
+			fn: func(opts ...Option) verifier {
+				c := NewCounter(opts...)
+				c.Expect().With("code", "200").AddedTimes(2)
+				c.Expect().With("code", "500").Never()
+				c.Expect().AddedTimes(3)
+				c.With("code", "200", "method", "GET").Add(1)
+				c.With("code", "200", "method", "PUT").Add(1)
+				c.With("code", "404", "method", "PUT").Add(1)
+				return c
+			},
+		}, {
+			description: "counter added the wrong number of times",
+			fn: func(opts ...Option) verifier {
+				c := NewCounter(opts...)
+				c.Expect().With("code", "200").AddedTimes(3)
+				c.Expect().With("code", "500").Never()
+				c.With("code", "200").Add(1)
+				c.With("code", "500").Add(1)
+				return c
+			},
+			errors: []string{
+				`counter {code="200"}: want 3 calls to Add, got 1`,
+				`counter {code="500"}: want 0 calls to Add, got 1`,
+			},
+		}, {
+			description: "counter expectation with invalid labels",
+			fn: func(opts ...Option) verifier {
+				c := NewCounter(opts...)
+				c.Expect().With("code").Never()
+				return c
+			},
+			errors: []string{"must be a multiple of 2"},
+		}, {
+			description: "gauge final value",
+			fn: func(opts ...Option) verifier {
+				g := NewGauge(opts...)
+				g.Expect().FinalValue(5)
+				g.Expect().With("queue", "b").Never()
+				g.With("queue", "a").Set(10)
+				g.With("queue", "a").Add(-5)
+				return g
+			},
+		}, {
+			description: "gauge with the wrong final value",
+			fn: func(opts ...Option) verifier {
+				g := NewGauge(opts...)
+				g.Expect().With("queue", "a").FinalValue(5)
+				g.Expect().With("queue", "b").FinalValue(5)
+				g.Expect().Never()
+				g.With("queue", "a").Set(10)
+				return g
+			},
+			errors: []string{
+				`gauge {queue="a"}: want a final value of 5, got 10`,
+				`gauge {queue="b"}: want a final value of 5, but it was never updated`,
+				`gauge {}: want no updates, got 10 for {queue="a"}`,
+			},
+		}, {
+			description: "histogram observed between",
+			fn: func(opts ...Option) verifier {
+				h := NewHistogram(opts...)
+				h.Expect().ObservedBetween(0, 1)
+				h.Expect().With("route", "/x").ObservedTimes(2)
+				h.Expect().With("route", "/y").Never()
+				h.With("route", "/x").Observe(0)
+				h.With("route", "/x").Observe(1)
+				return h
+			},
+		}, {
+			description: "histogram observed outside the range",
+			fn: func(opts ...Option) verifier {
+				h := NewHistogram(opts...)
+				h.Expect().With("route", "/x").ObservedBetween(0, 1)
+				h.Expect().With("route", "/y").ObservedBetween(0, 1)
+				h.Expect().Never()
+				h.With("route", "/x").Observe(1.5)
+				return h
+			},
+			errors: []string{
+				`histogram {route="/x"}: want observations between 0 and 1, got 1.5 for {route="/x"}`,
+				`histogram {route="/y"}: want observations between 0 and 1, got none`,
+				`histogram {}: want 0 observations, got 1`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := tc.fn().Verify()
+			if len(tc.errors) == 0 {
+				assert.NoError(err)
+			} else {
+				assert.Error(err)
+				for _, e := range tc.errors {
+					assert.ErrorContains(err, e)
+				}
+			}
+
+			tb := &fakeTB{TB: t}
+			tc.fn(WithT(tb))
+			assert.Empty(tb.Errors())
+			tb.finish()
+
+			errs := tb.Errors()
+			if assert.Len(errs, len(tc.errors)) {
+				for i, e := range tc.errors {
+					assert.Contains(errs[i], e)
+				}
+			}
+		})
+	}
+}
+
+type verifier interface {
+	Verify() error
+}
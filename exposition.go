@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	errMissingName   = errors.New("metric name is required")
+	errReservedLabel = errors.New("label is reserved")
+)
+
+// DefaultBuckets are the histogram bucket upper bounds used by
+// WriteExposition unless BucketBounds is used.  They match the defaults of the
+// Prometheus client.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// WriteExposition writes all of the series of the metric to w in the
+// Prometheus text exposition format, using the provided metric name.  If the
+// name is empty, the name set using Name is used, and it is an error if there
+// is no name.  Histograms are written as cumulative _bucket series along with
+// _sum and _count series, so it is an error if a histogram series has a label
+// named le.
+//
+// The metric must be a *Counter, *Gauge or *Histogram.
+func WriteExposition(w io.Writer, name string, metric any) error {
+	var meta Metadata
+	switch m := metric.(type) {
+	case *Counter:
+		meta = m.Metadata()
+	case *Gauge:
+		meta = m.Metadata()
+	case *Histogram:
+		meta = m.Metadata()
+	default:
+		return fmt.Errorf("unsupported metric type %T", metric)
+	}
+
+	if name == "" {
+		name = meta.Name
+	}
+	if name == "" {
+		return errMissingName
+	}
+
+	var b strings.Builder
+	if meta.Help != "" {
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, helpEscaper.Replace(meta.Help))
+	}
+
+	switch m := metric.(type) {
+	case *Counter:
+		writeSamples(&b, name, "counter", m.Snapshot())
+	case *Gauge:
+		writeSamples(&b, name, "gauge", m.Snapshot())
+	case *Histogram:
+		if err := writeHistogram(&b, name, m.bucketBounds(), m.Snapshot()); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteExposition writes all of the metrics created by the provider to w in
+// the Prometheus text exposition format, sorted by name.
+func (p *Provider) WriteExposition(w io.Writer) error {
+	p.m.Lock()
+	metrics := make(map[string]any, len(p.counters)+len(p.gauges)+len(p.histograms))
+	for name, c := range p.counters {
+		metrics[name] = c
+	}
+	for name, g := range p.gauges {
+		metrics[name] = g
+	}
+	for name, h := range p.histograms {
+		metrics[name] = h
+	}
+	p.m.Unlock()
+
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := WriteExposition(w, name, metrics[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeSamples(b *strings.Builder, name, typ string, s *Snapshot) {
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, typ)
+	for _, v := range s.series {
+		writeLine(b, name, v.labels, "", "", v.value)
+	}
+}
+
+func writeHistogram(b *strings.Builder, name string, bounds []float64, s *Snapshot) error {
+	fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+	for _, v := range s.series {
+		for _, t := range v.labels {
+			if t.label == "le" {
+				return fmt.Errorf("%w: histogram %s has the label le, which holds the bucket bounds",
+					errReservedLabel, name)
+			}
+		}
+
+		counts := v.obs.buckets(bounds)
+		for i, bound := range bounds {
+			writeLine(b, name+"_bucket", v.labels, "le", formatFloat(bound), float64(counts[i]))
+		}
+		writeLine(b, name+"_bucket", v.labels, "le", "+Inf", float64(v.obs.count))
+		writeLine(b, name+"_sum", v.labels, "", "", v.obs.sum)
+		writeLine(b, name+"_count", v.labels, "", "", float64(v.obs.count))
+	}
+
+	return nil
+}
+
+// writeLine writes a single sample, with an optional extra label such as le.
+func writeLine(b *strings.Builder, name string, lvp []tuple, extra, extraValue string, value float64) {
+	b.WriteString(name)
+
+	if len(lvp) > 0 || extra != "" {
+		pairs := make([]string, 0, len(lvp)+1)
+		for _, t := range lvp {
+			pairs = append(pairs, t.label+`="`+escapeLabelValue(t.value)+`"`)
+		}
+		if extra != "" {
+			pairs = append(pairs, extra+`="`+extraValue+`"`)
+		}
+		b.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	b.WriteString(" " + formatFloat(value) + "\n")
+}
+
+// cumulativeBuckets returns the number of values less than or equal to each of
+// the bounds, which must be sorted.
+func cumulativeBuckets(values, bounds []float64) []uint64 {
+	counts := make([]uint64, len(bounds))
+	for _, v := range values {
+		i := sort.SearchFloat64s(bounds, v)
+		if i < len(counts) {
+			counts[i]++
+		}
+	}
+
+	for i := 1; i < len(counts); i++ {
+		counts[i] += counts[i-1]
+	}
+
+	return counts
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteExposition(t *testing.T) {
+	tests := []struct {
+		description string
+		name        string
+		metric      func() any
+		expected    string
+		expectErr   bool
+	}{
+		{
+			description: "an empty counter",
+			name:        "requests_total",
+			metric:      func() any { return NewCounter() },
+			expected:    "# TYPE requests_total counter\n",
+		}, {
+			description: "a counter",
+			name:        "requests_total",
+			metric: func() any {
+				c := NewCounter()
+				c.With("method", "GET", "code", "500").Add(1)
+				c.With("method", "GET", "code", "200").Add(2)
+				return c
+			},
+			expected: `# TYPE requests_total counter
+requests_total{method="GET",code="200"} 2
+requests_total{method="GET",code="500"} 1
+`,
+		}, {
+			description: "the configured name and help",
+			metric: func() any {
+				c := NewCounter(Name("requests_total"), Help("The number of requests.\nBy \\ code."))
+				c.With("code", "200").Add(1)
+				return c
+			},
+			expected: `# HELP requests_total The number of requests.\nBy \\ code.
+# TYPE requests_total counter
+requests_total{code="200"} 1
+`,
+		}, {
+			description: "the provided name overrides the configured name",
+			name:        "temperature",
+			metric:      func() any { return NewGauge(Name("ignored"), Help("The temperature.")) },
+			expected: `# HELP temperature The temperature.
+# TYPE temperature gauge
+`,
+		}, {
+			description: "a gauge with escaped values",
+			name:        "temperature",
+			metric: func() any {
+				g := NewGauge()
+				g.Set(math.Inf(1))
+				g.With("path", "C:\\temp\n\"x\"").Set(-1.5)
+				return g
+			},
+			expected: `# TYPE temperature gauge
+temperature +Inf
+temperature{path="C:\\temp\n\"x\""} -1.5
+`,
+		}, {
+			description: "a histogram with buckets",
+			name:        "latency_seconds",
+			metric: func() any {
+				h := NewHistogram(BucketBounds(1, 0.5))
+				h.With("route", "/x").Observe(0.25)
+				h.With("route", "/x").Observe(0.5)
+				h.With("route", "/x").Observe(2)
+				return h
+			},
+			expected: `# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/x",le="0.5"} 2
+latency_seconds_bucket{route="/x",le="1"} 2
+latency_seconds_bucket{route="/x",le="+Inf"} 3
+latency_seconds_sum{route="/x"} 2.75
+latency_seconds_count{route="/x"} 3
+`,
+		}, {
+			description: "a histogram with the default buckets",
+			name:        "size",
+			metric: func() any {
+				h := NewHistogram()
+				h.Observe(0.1)
+				return h
+			},
+			expected: `# TYPE size histogram
+size_bucket{le="0.005"} 0
+size_bucket{le="0.01"} 0
+size_bucket{le="0.025"} 0
+size_bucket{le="0.05"} 0
+size_bucket{le="0.1"} 1
+size_bucket{le="0.25"} 1
+size_bucket{le="0.5"} 1
+size_bucket{le="1"} 1
+size_bucket{le="2.5"} 1
+size_bucket{le="5"} 1
+size_bucket{le="10"} 1
+size_bucket{le="+Inf"} 1
+size_sum 0.1
+size_count 1
+`,
+		}, {
+			description: "a metric without a name",
+			metric: func() any {
+				c := NewCounter()
+				c.With("code", "200").Add(1)
+				return c
+			},
+			expectErr: true,
+		}, {
+			description: "a histogram with an le label",
+			name:        "lat",
+			metric: func() any {
+				h := NewHistogram(BucketBounds(5))
+				h.With("le", "0.005").Observe(1)
+				return h
+			},
+			expectErr: true,
+		}, {
+			description: "an unsupported metric",
+			metric:      func() any { return "invalid" },
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var b strings.Builder
+			err := WriteExposition(&b, tc.name, tc.metric())
+			if tc.expectErr {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.expected, b.String())
+		})
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestProviderWriteExposition(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewProvider(BucketBounds(1))
+	p.NewHistogram("c_seconds", 0).Observe(0.5)
+	p.NewGauge("b").Set(1)
+	p.NewCounter("a_total").Add(1)
+
+	var b strings.Builder
+	assert.NoError(p.WriteExposition(&b))
+	assert.Equal(`# TYPE a_total counter
+a_total 1
+# TYPE b gauge
+b 1
+# TYPE c_seconds histogram
+c_seconds_bucket{le="1"} 1
+c_seconds_bucket{le="+Inf"} 1
+c_seconds_sum 0.5
+c_seconds_count 1
+`, b.String())
+
+	assert.Error(p.WriteExposition(errWriter{}))
+}
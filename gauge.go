@@ -5,6 +5,8 @@ package mockitmetrics
 
 import (
 	"sync"
+	"sync/atomic"
+	"testing"
 
 	kit "github.com/go-kit/kit/metrics"
 )
@@ -21,19 +23,49 @@ func NewGauge(opts ...Option) *Gauge {
 			opt.gaugeApply(&g)
 		}
 	}
+	g.invalid = validateLabels(g.expectedLabels, nil, true)
+
+	if g.t != nil {
+		g.t.Helper()
+	}
+	if err := validateName(g.meta.Name); err != nil {
+		g.panic(err)
+	}
 
 	return &g
 }
 
 // Gauge is a mock gauge.
 type Gauge struct {
-	value          map[string]float64
-	delimiter      string
-	panic          func(any)
-	m              sync.Mutex
-	root           *Gauge
-	expectedLabels *[]string
-	lvp            []tuple
+	value           map[string]*gaugeSeries
+	labels          map[string][]tuple
+	index           sync.Map
+	delimiter       string
+	panic           func(any)
+	meta            Metadata
+	m               sync.Mutex
+	root            *Gauge
+	expectedLabels  *[]string
+	lvp             []tuple
+	key             string
+	invalid         error
+	series          atomic.Pointer[gaugeSeries]
+	rejectDelimiter bool
+	strict          bool
+	anyLabelOrder   bool
+	duplicates      DuplicatePolicy
+	valueRules      map[string]*valueRule
+	maxSeries       int
+	t               testing.TB
+	discard         bool
+	resets          uint64
+	expectations    []expectation
+	events          eventLog
+	changed         notifier
+	listeners       listeners
+	spy             kit.Gauge
+	recordHistory   bool
+	writes          map[string]*atomic.Uint64
 }
 
 var _ kit.Gauge = (*Gauge)(nil)
@@ -45,26 +77,85 @@ func (g *Gauge) With(labelValues ...string) kit.Gauge {
 		root = g.root
 	}
 
+	if root.t != nil {
+		root.t.Helper()
+	}
+
+	var spy kit.Gauge
+	if g.spy != nil {
+		spy = g.spy.With(labelValues...)
+	}
+
+	if g.discard {
+		return &Gauge{
+			root:    root,
+			discard: true,
+			spy:     spy,
+		}
+	}
+
 	lvp, err := convert(labelValues)
 	if err != nil {
 		goto failure
 	}
 
-	lvp = append(g.lvp, lvp...)
+	lvp, err = mergeLabels(g.lvp, lvp, root.duplicates)
+	if err != nil {
+		goto failure
+	}
 
-	err = validateLabels(root.expectedLabels, lvp, false)
+	if root.anyLabelOrder {
+		lvp = orderLabels(root.expectedLabels, lvp)
+		err = validateLabelSet(root.expectedLabels, lvp)
+	} else {
+		err = validateLabels(root.expectedLabels, lvp, false)
+	}
+	if err != nil {
+		goto failure
+	}
+
+	if root.rejectDelimiter {
+		err = checkDelimiter(lvp, root.delimiter)
+		if err != nil {
+			goto failure
+		}
+	}
+
+	if root.strict {
+		err = checkPrometheus(lvp)
+		if err != nil {
+			goto failure
+		}
+	}
+
+	err = checkValues(root.valueRules, lvp)
 	if err != nil {
 		goto failure
 	}
 
 	return &Gauge{
-		root: root,
-		lvp:  lvp,
+		root:    root,
+		lvp:     lvp,
+		key:     seriesKey(lvp),
+		invalid: validateLabels(root.expectedLabels, lvp, true),
+		spy:     spy,
 	}
 
 failure:
-	root.panic(err)
-	return nil
+	root.panic(root.meta.wrap(err))
+	return &Gauge{
+		root:    root,
+		discard: true,
+		spy:     spy,
+	}
+}
+
+// tb returns the testing.TB failures are reported to, if any.
+func (g *Gauge) tb() testing.TB {
+	if g.root != nil {
+		return g.root.t
+	}
+	return g.t
 }
 
 func (g *Gauge) update(value float64, delta bool) {
@@ -73,42 +164,138 @@ func (g *Gauge) update(value float64, delta bool) {
 		root = g
 	}
 
-	if err := validateLabels(root.expectedLabels, g.lvp, true); err != nil {
-		root.panic(err)
+	if root.t != nil {
+		root.t.Helper()
+	}
+
+	if g.discard {
+		return
+	}
+
+	if g.invalid != nil {
+		root.panic(root.meta.wrap(g.invalid))
 		return
 	}
 
-	label := joinValues(g.lvp, root.delimiter)
+	if g.fastUpdate(root, value, delta) {
+		return
+	}
+
+	caller := root.events.caller()
 
 	root.m.Lock()
-	defer root.m.Unlock()
 
 	if root.value == nil {
-		root.value = map[string]float64{}
+		root.value = map[string]*gaugeSeries{}
+		root.labels = map[string][]tuple{}
+		root.writes = map[string]*atomic.Uint64{}
 	}
 
-	if _, ok := root.value[label]; !ok {
-		root.value[label] = 0.0
+	s, ok := root.value[g.key]
+	if !ok {
+		if err := checkSeries(root.maxSeries, len(root.value), g.lvp); err != nil {
+			root.m.Unlock()
+			root.panic(root.meta.wrap(err))
+			return
+		}
+		s = &gaugeSeries{}
+		root.order(s, g.lvp)
+		root.value[g.key] = s
+		root.labels[g.key] = g.lvp
+		root.index.Store(g.key, s)
 	}
 
+	op := OpSet
 	if delta {
-		root.value[label] += value
-	} else {
-		root.value[label] = value
+		op = OpAdd
+	}
+	current, _ := s.update(value, delta, root.recordHistory)
+	g.series.Store(s)
+	root.events.add(KindGauge, op, g.lvp, value, caller)
+	root.changed.notify()
+	publish := root.listeners.prepare(KindGauge, op, g.lvp, value, current)
+	root.m.Unlock()
+
+	publish()
+}
+
+// order makes the writes of the new series comparable with those of the other
+// series that share its key in Value(), if there are any, so Value() can tell
+// which was written last.  Series that don't share a key aren't ordered, so
+// they don't contend on the order.  The root lock must be held by the caller.
+func (g *Gauge) order(s *gaugeSeries, lvp []tuple) {
+	key := joinValues(lvp, g.delimiter)
+	writes, ok := g.writes[key]
+	if !ok {
+		g.writes[key] = nil
+		return
 	}
+
+	if writes == nil {
+		writes = new(atomic.Uint64)
+		g.writes[key] = writes
+		for k, other := range g.value {
+			if joinValues(g.labels[k], g.delimiter) == key {
+				other.order(writes)
+			}
+		}
+	}
+	s.order(writes)
+}
+
+// fastUpdate updates the series found by an earlier update without taking the
+// root lock.  It returns false if the root lock is needed.
+func (g *Gauge) fastUpdate(root *Gauge, value float64, delta bool) bool {
+	if root.events.record || !root.listeners.empty() {
+		return false
+	}
+
+	s := g.series.Load()
+	if s == nil {
+		found, ok := root.index.Load(g.key)
+		if !ok {
+			return false
+		}
+		s = found.(*gaugeSeries)
+		g.series.Store(s)
+	}
+
+	if _, ok := s.update(value, delta, root.recordHistory); !ok {
+		return false
+	}
+
+	root.changed.notify()
+	return true
 }
 
 // Set sets the gauge to the provided value.
 func (g *Gauge) Set(value float64) {
+	if t := g.tb(); t != nil {
+		t.Helper()
+	}
+	if g.spy != nil {
+		g.spy.Set(value)
+	}
 	g.update(value, false)
 }
 
 // Add adds the provided delta to the gauge.
 func (g *Gauge) Add(delta float64) {
+	if t := g.tb(); t != nil {
+		t.Helper()
+	}
+	if g.spy != nil {
+		g.spy.Add(delta)
+	}
 	g.update(delta, true)
 }
 
 // Value returns the current value of the gauge.
+//
+// The keys are the label values joined by the delimiter, with any delimiter in
+// a value escaped by a backslash.  Series that differ only by label names
+// share a key, which holds the value of the series written last; use Samples()
+// to tell them apart.
 func (g *Gauge) Value() map[string]float64 {
 	root := g.root
 	if root == nil {
@@ -123,9 +310,146 @@ func (g *Gauge) Value() map[string]float64 {
 	}
 
 	rv := map[string]float64{}
+	written := map[string]uint64{}
+
+	for k, s := range root.value {
+		key := joinValues(root.labels[k], root.delimiter)
+		v, w := s.latest()
+		if w >= written[key] {
+			rv[key] = v
+			written[key] = w
+		}
+	}
+	return rv
+}
+
+// Samples returns the current value of each series in the gauge.
+func (g *Gauge) Samples() []Sample {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	if len(root.value) == 0 {
+		return nil
+	}
 
-	for k, v := range root.value {
-		rv[k] = v
+	rv := make([]Sample, 0, len(root.value))
+	for k, s := range root.value {
+		rv = append(rv, Sample{
+			Labels: toLabels(root.labels[k]),
+			Value:  s.get(),
+		})
 	}
+	sortSamples(rv)
+
 	return rv
 }
+
+// Get returns the value of the series with exactly the provided label name
+// and value pairs, in any order.  If no series matches, false is returned.
+// If the labels were passed to With in more than one order, they match more
+// than one series; the ambiguity is reported and false is returned.
+func (g *Gauge) Get(labelValues ...string) (float64, bool) {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	s, err := g.lookup(labelValues)
+	if err != nil {
+		root.panic(root.meta.wrap(err))
+		return 0, false
+	}
+	if s == nil {
+		return 0, false
+	}
+
+	return s.get(), true
+}
+
+// lookup returns the series with exactly the provided label name and value
+// pairs, in any order, or nil if no series matches.
+func (g *Gauge) lookup(labelValues []string) (*gaugeSeries, error) {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	want, err := convert(labelValues)
+	if err != nil {
+		return nil, nil
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	k, ok, err := findLabels(root.labels, want)
+	if !ok {
+		return nil, err
+	}
+
+	return root.value[k], nil
+}
+
+// Events returns the events recorded by the tree of gauges.  Events are only
+// recorded when RecordEvents or RecordCallers is used.
+func (g *Gauge) Events() []Event {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return root.events.events()
+}
+
+// Snapshot returns an immutable copy of all of the series of the gauge.
+func (g *Gauge) Snapshot() *Snapshot {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	series := make([]snapshotSeries, 0, len(root.value))
+	for k, s := range root.value {
+		series = append(series, snapshotSeries{
+			key:    k,
+			labels: root.labels[k],
+			value:  s.get(),
+		})
+	}
+
+	return newSnapshot(KindGauge, root.resets, series)
+}
+
+// Reset discards all of the series and events recorded by the tree of
+// gauges.  Options and expectations are kept.
+func (g *Gauge) Reset() {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	for k, s := range root.value {
+		s.discard()
+		root.index.Delete(k)
+	}
+	root.value = nil
+	root.labels = nil
+	root.writes = nil
+	root.resets++
+	root.events.list = nil
+	root.changed.notify()
+}
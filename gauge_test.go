@@ -4,6 +4,7 @@
 package mockitmetrics
 
 import (
+	"regexp"
 	"testing"
 
 	kit "github.com/go-kit/kit/metrics"
@@ -149,6 +150,186 @@ func TestGauge(t *testing.T) {
 			},
 			opt:         ExpectLabels("one", "two"),
 			expectPanic: true,
+		}, {
+			description: "values containing the delimiter do not collide",
+			fn: func(g kit.Gauge) {
+				g.With("a", "x.y").Add(1)
+				g.With("a", "x", "b", "y").Add(1)
+				g.With("a", `x\y`).Add(1)
+			},
+			expected: map[string]float64{
+				`x\.y`: 1.0,
+				"x.y":  1.0,
+				`x\\y`: 1.0,
+			},
+		}, {
+			description: "series sharing a key keep the last write",
+			fn: func(g kit.Gauge) {
+				g.With("a", "x").Set(5)
+				g.With("b", "x").Set(3)
+				g.With("c", "y").Set(1)
+				g.With("d", "y").Set(2)
+				g.With("c", "y").Add(5)
+				g.With("e", "y").Set(4)
+				g.With("d", "y").Add(1)
+			},
+			expected: map[string]float64{
+				"x": 3.0,
+				"y": 3.0,
+			},
+		}, {
+			description: "reject values containing the delimiter",
+			fn: func(g kit.Gauge) {
+				g.With("a", "x.y")
+			},
+			opt:         RejectDelimiter(),
+			expectPanic: true,
+		}, {
+			description: "reject values containing a custom delimiter",
+			fn: func(g kit.Gauge) {
+				g.With("a", "x.y").Add(1)
+				g.With("a", "x-y")
+			},
+			opts:        []Option{RejectDelimiter(), Delimiter("-")},
+			expectPanic: true,
+		}, {
+			description: "allowed label values",
+			fn: func(g kit.Gauge) {
+				g.With("code", "200", "method", "GET").Add(1)
+				g.With("code", "500").With("method", "PUT").Add(1)
+			},
+			opts: []Option{
+				ExpectLabelValues("code", "200", "404"),
+				ExpectLabelValues("code", "500"),
+				ExpectLabelPattern("method", regexp.MustCompile("^(GET|PUT)$")),
+			},
+			expected: map[string]float64{
+				"200.GET": 1.0,
+				"500.PUT": 1.0,
+			},
+		}, {
+			description: "error when a label value is not allowed",
+			fn: func(g kit.Gauge) {
+				g.With("code", "201")
+			},
+			opt:         ExpectLabelValues("code", "200", "404"),
+			expectPanic: true,
+		}, {
+			description: "error when a label value does not match the pattern",
+			fn: func(g kit.Gauge) {
+				g.With("code", "200").With("path", "/users/1234")
+			},
+			opts: []Option{
+				ExpectLabelPattern("path", regexp.MustCompile("^/[a-z]+$")),
+				ExpectLabelValues("code", "200"),
+			},
+			expectPanic: true,
+		}, {
+			description: "labels in any order share a series",
+			fn: func(g kit.Gauge) {
+				g.With("b", "2", "a", "1").Add(1)
+				g.With("a", "1").With("b", "2").Add(1)
+				g.With("b", "3").With("a", "1").Add(1)
+			},
+			opts: []Option{ExpectLabels("a", "b"), AnyLabelOrder()},
+			expected: map[string]float64{
+				"1.2": 2.0,
+				"1.3": 1.0,
+			},
+		}, {
+			description: "labels in any order are sorted by name without expected labels",
+			fn: func(g kit.Gauge) {
+				g.With("b", "2", "a", "1").Add(1)
+				g.With("a", "1", "b", "2").Add(1)
+			},
+			opt: AnyLabelOrder(),
+			expected: map[string]float64{
+				"1.2": 2.0,
+			},
+		}, {
+			description: "labels in any order must still be expected",
+			fn: func(g kit.Gauge) {
+				g.With("b", "2", "c", "1")
+			},
+			opts:        []Option{ExpectLabels("a", "b"), AnyLabelOrder()},
+			expectPanic: true,
+		}, {
+			description: "labels in any order must all be present",
+			fn: func(g kit.Gauge) {
+				g.With("b", "2").Add(1)
+			},
+			opts:        []Option{ExpectLabels("a", "b"), AnyLabelOrder()},
+			expectPanic: true,
+		}, {
+			description: "error when labels are out of order",
+			fn: func(g kit.Gauge) {
+				g.With("b", "2", "a", "1")
+			},
+			opt:         ExpectLabels("a", "b"),
+			expectPanic: true,
+		}, {
+			description: "error when a label is repeated",
+			fn: func(g kit.Gauge) {
+				g.With("a", "1").With("a", "2")
+			},
+			expectPanic: true,
+		}, {
+			description: "error when a label is repeated in the same call",
+			fn: func(g kit.Gauge) {
+				g.With("a", "1", "a", "2")
+			},
+			opt:         OnDuplicateLabel(DuplicateFail),
+			expectPanic: true,
+		}, {
+			description: "the last value of a repeated label wins",
+			fn: func(g kit.Gauge) {
+				g.With("a", "1", "b", "2").With("a", "3").Add(1)
+				g.With("a", "3", "b", "2").Add(1)
+			},
+			opt: OnDuplicateLabel(DuplicateLastWins),
+			expected: map[string]float64{
+				"3.2": 2.0,
+			},
+		}, {
+			description: "the first value of a repeated label wins",
+			fn: func(g kit.Gauge) {
+				g.With("a", "1", "b", "2").With("a", "3").Add(1)
+				g.With("a", "1").With("b", "2", "b", "4").Add(1)
+			},
+			opt: OnDuplicateLabel(DuplicateFirstWins),
+			expected: map[string]float64{
+				"1.2": 2.0,
+			},
+		}, {
+			description: "valid Prometheus labels",
+			fn: func(g kit.Gauge) {
+				g.With("http_code", "200", "_method", "GÉT").Add(1)
+			},
+			opt: PrometheusStrict(),
+			expected: map[string]float64{
+				"200.GÉT": 1.0,
+			},
+		}, {
+			description: "error when a label is not a valid Prometheus label",
+			fn: func(g kit.Gauge) {
+				g.With("http-code", "200")
+			},
+			opt:         PrometheusStrict(),
+			expectPanic: true,
+		}, {
+			description: "error when a label uses the reserved prefix",
+			fn: func(g kit.Gauge) {
+				g.With("code", "200").With("__name__", "x")
+			},
+			opt:         PrometheusStrict(),
+			expectPanic: true,
+		}, {
+			description: "error when a value is not valid UTF-8",
+			fn: func(g kit.Gauge) {
+				g.With("code", "\xff")
+			},
+			opt:         PrometheusStrict(),
+			expectPanic: true,
 		},
 	}
 
@@ -169,3 +350,35 @@ func TestGauge(t *testing.T) {
 		})
 	}
 }
+
+func TestGaugeSamples(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewGauge()
+	assert.Nil(g.Samples())
+
+	g.Set(3)
+	g.With("queue", "a").Set(5)
+	g.With("queue", "a").Add(-1)
+
+	assert.Equal([]Sample{
+		{
+			Labels: []Label{},
+			Value:  3,
+		}, {
+			Labels: []Label{{Name: "queue", Value: "a"}},
+			Value:  4,
+		},
+	}, g.Samples())
+
+	got, ok := g.Get()
+	assert.True(ok)
+	assert.Equal(3.0, got)
+
+	got, ok = g.Get("queue", "a")
+	assert.True(ok)
+	assert.Equal(4.0, got)
+
+	_, ok = g.Get("queue", "b")
+	assert.False(ok)
+}
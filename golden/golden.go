@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+// Package golden compares the series recorded by the mock metrics in
+// mockitmetrics against golden files.
+//
+// The golden files are stable, sorted text files kept in the Dir directory.
+// Running the tests with the -update flag rewrites the golden files with the
+// current results instead of comparing against them:
+//
+//	go test ./... -run TestMyService -update
+//
+// This package defines the -update flag, so the package under test shouldn't
+// define its own.  If another package has already defined a boolean -update
+// flag, that flag is used instead.
+package golden
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xmidt-org/mockitmetrics"
+)
+
+// Dir is the directory the golden files are kept in.
+var Dir = "testdata"
+
+func init() {
+	defineUpdate(flag.CommandLine)
+}
+
+// defineUpdate defines the -update flag, unless it is already defined.
+func defineUpdate(fs *flag.FlagSet) {
+	if fs.Lookup("update") == nil {
+		fs.Bool("update", false, "update the golden files instead of comparing against them")
+	}
+}
+
+// updating returns true if the golden files should be rewritten.
+func updating() bool {
+	if g, ok := flag.Lookup("update").Value.(flag.Getter); ok {
+		b, _ := g.Get().(bool)
+		return b
+	}
+
+	return false
+}
+
+type tHelper interface {
+	Helper()
+}
+
+// Assert compares the serialized series of the metric against the golden file
+// named name.golden, reporting a diff if they don't match.  When the tests are
+// run with -update the golden file is rewritten instead.
+//
+// The metric must be a *mockitmetrics.Counter, *mockitmetrics.Gauge,
+// *mockitmetrics.Histogram or *mockitmetrics.Provider.
+func Assert(t assert.TestingT, name string, metric any) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	got, err := Serialize(metric)
+	if err != nil {
+		return assert.Fail(t, err.Error())
+	}
+
+	file := filepath.Join(Dir, name+".golden")
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return assert.Fail(t, err.Error())
+		}
+		if err := os.WriteFile(file, []byte(got), 0o644); err != nil { //nolint:gosec
+			return assert.Fail(t, err.Error())
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return assert.Fail(t, fmt.Sprintf("golden file %s does not exist, run the tests with -update to create it", file))
+	}
+	if err != nil {
+		return assert.Fail(t, err.Error())
+	}
+
+	return assert.Equal(t, string(want), got, "the series do not match golden file %s, run the tests with -update to update it", file)
+}
+
+// Serialize returns the stable text form of the series of the metric used in
+// the golden files.  Each metric starts with a header holding its kind and its
+// name, either the name it has in the provider or the one set by the Name
+// option.
+//
+// The metric must be a *mockitmetrics.Counter, *mockitmetrics.Gauge,
+// *mockitmetrics.Histogram or *mockitmetrics.Provider.
+func Serialize(metric any) (string, error) {
+	var b strings.Builder
+
+	if p, ok := metric.(*mockitmetrics.Provider); ok {
+		for i, name := range p.Names() {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+
+			var m any
+			switch {
+			case p.Counter(name) != nil:
+				m = p.Counter(name)
+			case p.Gauge(name) != nil:
+				m = p.Gauge(name)
+			default:
+				m = p.Histogram(name)
+			}
+
+			if err := serialize(&b, name, m); err != nil {
+				return "", err
+			}
+		}
+		return b.String(), nil
+	}
+
+	if err := serialize(&b, "", metric); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// serialize writes the series of the metric.  If name is empty, the name set
+// by the Name option is used, if any.
+func serialize(b *strings.Builder, name string, metric any) error {
+	if m, ok := metric.(interface{ Metadata() mockitmetrics.Metadata }); ok && name == "" {
+		name = m.Metadata().Name
+	}
+
+	header := func(kind string) {
+		b.WriteString(kind)
+		if name != "" {
+			b.WriteString(" " + name)
+		}
+		b.WriteString("\n")
+	}
+
+	switch m := metric.(type) {
+	case *mockitmetrics.Counter:
+		header("counter")
+		for _, s := range m.Samples() {
+			b.WriteString(format(s.Labels) + " " + formatFloat(s.Value) + "\n")
+		}
+	case *mockitmetrics.Gauge:
+		header("gauge")
+		for _, s := range m.Samples() {
+			b.WriteString(format(s.Labels) + " " + formatFloat(s.Value) + "\n")
+		}
+	case *mockitmetrics.Histogram:
+		header("histogram")
+		for _, s := range m.Samples() {
+			values := make([]string, 0, len(s.Values))
+			for _, v := range s.Values {
+				values = append(values, formatFloat(v))
+			}
+			b.WriteString(format(s.Labels) + " [" + strings.Join(values, " ") + "]\n")
+		}
+	default:
+		return fmt.Errorf("unsupported metric type %T", metric)
+	}
+
+	return nil
+}
+
+// format renders the labels like {code="500", method="GET"}.
+func format(labels []mockitmetrics.Label) string {
+	pairs := make([]string, 0, len(labels))
+	for _, l := range labels {
+		pairs = append(pairs, l.Name+"="+strconv.Quote(l.Value))
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package golden
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xmidt-org/mockitmetrics"
+)
+
+type mockT struct {
+	errors []string
+}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+// setUpdate sets the -update flag until the end of the test.
+func setUpdate(t *testing.T, v bool) {
+	t.Helper()
+
+	prev := flag.Lookup("update").Value.String()
+	require.NoError(t, flag.Set("update", strconv.FormatBool(v)))
+	t.Cleanup(func() {
+		flag.Set("update", prev) //nolint:errcheck
+	})
+}
+
+func TestSerialize(t *testing.T) {
+	p := mockitmetrics.NewProvider()
+	p.NewCounter("requests_total").With("method", "GET", "code", "500").Add(1)
+	p.NewCounter("requests_total").With("method", "GET", "code", "200").Add(2)
+	p.NewGauge("in_flight").Set(3)
+	p.NewHistogram("latency", 0).With("route", "/x").Observe(0.5)
+	p.NewHistogram("latency", 0).With("route", "/x").Observe(1.5)
+
+	got, err := Serialize(p)
+	require.NoError(t, err)
+	assert.Equal(t, `gauge in_flight
+{} 3
+
+histogram latency
+{route="/x"} [0.5 1.5]
+
+counter requests_total
+{method="GET", code="200"} 2
+{method="GET", code="500"} 1
+`, got)
+
+	got, err = Serialize(p.Gauge("in_flight"))
+	require.NoError(t, err)
+	assert.Equal(t, "gauge in_flight\n{} 3\n", got)
+
+	c := mockitmetrics.NewCounter(mockitmetrics.Name("http_requests_total"))
+	c.With("code", "200").Add(1)
+	got, err = Serialize(c)
+	require.NoError(t, err)
+	assert.Equal(t, "counter http_requests_total\n{code=\"200\"} 1\n", got)
+
+	got, err = Serialize(mockitmetrics.NewHistogram())
+	require.NoError(t, err)
+	assert.Equal(t, "histogram\n", got)
+
+	_, err = Serialize("invalid")
+	assert.Error(t, err)
+}
+
+func TestAssert(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := Dir
+	Dir = t.TempDir()
+	defer func() {
+		Dir = dir
+	}()
+	setUpdate(t, false)
+
+	c := mockitmetrics.NewCounter()
+	c.With("code", "200").Add(1)
+
+	// The golden file doesn't exist yet.
+	m := &mockT{}
+	assert.False(Assert(m, "sub/counter", c))
+	if assert.Len(m.errors, 1) {
+		assert.Contains(m.errors[0], "run the tests with -update to create it")
+	}
+
+	// Create it.
+	setUpdate(t, true)
+	m = &mockT{}
+	assert.True(Assert(m, "sub/counter", c))
+	assert.Empty(m.errors)
+	setUpdate(t, false)
+
+	b, err := os.ReadFile(filepath.Join(Dir, "sub", "counter.golden"))
+	assert.NoError(err)
+	assert.Equal("counter\n{code=\"200\"} 1\n", string(b))
+
+	// It matches.
+	m = &mockT{}
+	assert.True(Assert(m, "sub/counter", c))
+	assert.Empty(m.errors)
+
+	// It doesn't match anymore.
+	c.With("code", "500").Add(1)
+	m = &mockT{}
+	assert.False(Assert(m, "sub/counter", c))
+	if assert.Len(m.errors, 1) {
+		assert.Contains(m.errors[0], `+{code="500"} 1`)
+	}
+
+	// Unsupported metrics.
+	m = &mockT{}
+	assert.False(Assert(m, "sub/counter", 42))
+	assert.Len(m.errors, 1)
+}
+
+func TestUpdating(t *testing.T) {
+	setUpdate(t, false)
+	assert.False(t, updating())
+
+	setUpdate(t, true)
+	assert.True(t, updating())
+}
+
+func TestDefineUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	existing := fs.Bool("update", false, "update another package's golden files")
+
+	// The flag is already defined, so it must be reused rather than panic.
+	assert.NotPanics(func() {
+		defineUpdate(fs)
+	})
+	assert.NoError(fs.Parse([]string{"-update"}))
+	assert.True(*existing)
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	defineUpdate(fs)
+	assert.NotNil(fs.Lookup("update"))
+}
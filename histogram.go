@@ -5,6 +5,8 @@ package mockitmetrics
 
 import (
 	"sync"
+	"sync/atomic"
+	"testing"
 
 	kit "github.com/go-kit/kit/metrics"
 )
@@ -21,19 +23,50 @@ func NewHistogram(opts ...Option) *Histogram {
 			opt.histogramApply(&h)
 		}
 	}
+	h.invalid = validateLabels(h.expectedLabels, nil, true)
+
+	if h.t != nil {
+		h.t.Helper()
+	}
+	if err := validateName(h.meta.Name); err != nil {
+		h.panic(err)
+	}
 
 	return &h
 }
 
 // Histogram is a mock histogram.
 type Histogram struct {
-	value          map[string][]float64
-	delimiter      string
-	panic          func(any)
-	m              sync.Mutex
-	root           *Histogram
-	expectedLabels *[]string
-	lvp            []tuple
+	value           map[string]*histogramSeries
+	labels          map[string][]tuple
+	index           sync.Map
+	delimiter       string
+	panic           func(any)
+	meta            Metadata
+	m               sync.Mutex
+	root            *Histogram
+	expectedLabels  *[]string
+	lvp             []tuple
+	key             string
+	invalid         error
+	series          atomic.Pointer[histogramSeries]
+	rejectDelimiter bool
+	strict          bool
+	anyLabelOrder   bool
+	duplicates      DuplicatePolicy
+	valueRules      map[string]*valueRule
+	maxSeries       int
+	t               testing.TB
+	discard         bool
+	resets          uint64
+	expectations    []expectation
+	events          eventLog
+	changed         notifier
+	listeners       listeners
+	spy             kit.Histogram
+	buckets         []float64
+	interpolation   Interpolation
+	storage         storage
 }
 
 var _ kit.Histogram = (*Histogram)(nil)
@@ -45,26 +78,77 @@ func (h *Histogram) With(labelValues ...string) kit.Histogram {
 		root = h.root
 	}
 
+	if root.t != nil {
+		root.t.Helper()
+	}
+
+	var spy kit.Histogram
+	if h.spy != nil {
+		spy = h.spy.With(labelValues...)
+	}
+
+	if h.discard {
+		return &Histogram{
+			root:    root,
+			discard: true,
+			spy:     spy,
+		}
+	}
+
 	lvp, err := convert(labelValues)
 	if err != nil {
 		goto failure
 	}
 
-	lvp = append(h.lvp, lvp...)
+	lvp, err = mergeLabels(h.lvp, lvp, root.duplicates)
+	if err != nil {
+		goto failure
+	}
+
+	if root.anyLabelOrder {
+		lvp = orderLabels(root.expectedLabels, lvp)
+		err = validateLabelSet(root.expectedLabels, lvp)
+	} else {
+		err = validateLabels(root.expectedLabels, lvp, false)
+	}
+	if err != nil {
+		goto failure
+	}
+
+	if root.rejectDelimiter {
+		err = checkDelimiter(lvp, root.delimiter)
+		if err != nil {
+			goto failure
+		}
+	}
 
-	err = validateLabels(root.expectedLabels, lvp, false)
+	if root.strict {
+		err = checkPrometheus(lvp)
+		if err != nil {
+			goto failure
+		}
+	}
+
+	err = checkValues(root.valueRules, lvp)
 	if err != nil {
 		goto failure
 	}
 
 	return &Histogram{
-		root: root,
-		lvp:  lvp,
+		root:    root,
+		lvp:     lvp,
+		key:     seriesKey(lvp),
+		invalid: validateLabels(root.expectedLabels, lvp, true),
+		spy:     spy,
 	}
 
 failure:
-	root.panic(err)
-	return nil
+	root.panic(root.meta.wrap(err))
+	return &Histogram{
+		root:    root,
+		discard: true,
+		spy:     spy,
+	}
 }
 
 // Observe adds the provided value to the histogram.
@@ -74,27 +158,94 @@ func (h *Histogram) Observe(value float64) {
 		root = h
 	}
 
-	if err := validateLabels(root.expectedLabels, h.lvp, true); err != nil {
-		root.panic(err)
+	if root.t != nil {
+		root.t.Helper()
+	}
+
+	if h.spy != nil {
+		h.spy.Observe(value)
+	}
+
+	if h.discard {
+		return
+	}
+
+	if h.invalid != nil {
+		root.panic(root.meta.wrap(h.invalid))
+		return
+	}
+
+	if h.fastObserve(root, value) {
 		return
 	}
 
-	label := joinValues(h.lvp, root.delimiter)
+	caller := root.events.caller()
 
 	root.m.Lock()
-	defer root.m.Unlock()
 
 	if root.value == nil {
-		root.value = map[string][]float64{}
+		root.value = map[string]*histogramSeries{}
+		root.labels = map[string][]tuple{}
 	}
 
-	if _, ok := root.value[label]; !ok {
-		root.value[label] = []float64{}
+	s, ok := root.value[h.key]
+	if !ok {
+		if err := checkSeries(root.maxSeries, len(root.value), h.lvp); err != nil {
+			root.m.Unlock()
+			root.panic(root.meta.wrap(err))
+			return
+		}
+		s = &histogramSeries{obs: newObservations(&root.storage)}
+		root.value[h.key] = s
+		root.labels[h.key] = h.lvp
+		root.index.Store(h.key, s)
 	}
-	root.value[label] = append(root.value[label], value)
+	count, _ := s.observe(value)
+	h.series.Store(s)
+	root.events.add(KindHistogram, OpObserve, h.lvp, value, caller)
+	root.changed.notify()
+	publish := root.listeners.prepare(KindHistogram, OpObserve, h.lvp, value, float64(count))
+	root.m.Unlock()
+
+	publish()
 }
 
-// Value returns the current value of the histogram.
+// fastObserve adds the value to the series found by an earlier call to Observe
+// without taking the root lock.  It returns false if the root lock is needed.
+// Reservoir storage always needs it since the series share a random source.
+func (h *Histogram) fastObserve(root *Histogram, value float64) bool {
+	if root.events.record || !root.listeners.empty() || root.storage.mode == storeReservoir {
+		return false
+	}
+
+	s := h.series.Load()
+	if s == nil {
+		found, ok := root.index.Load(h.key)
+		if !ok {
+			return false
+		}
+		s = found.(*histogramSeries)
+		h.series.Store(s)
+	}
+
+	if _, ok := s.observe(value); !ok {
+		return false
+	}
+
+	root.changed.notify()
+	return true
+}
+
+// Value returns the current value of the histogram.  The returned map and
+// slices are independent copies that are safe to read and modify.
+//
+// Only the retained observations are returned, which is all of them unless
+// Reservoir or Streaming storage is used.
+//
+// The keys are the label values joined by the delimiter, with any delimiter in
+// a value escaped by a backslash.  Series that differ only by label names
+// share a key and their observations are combined; use Samples() to tell them
+// apart.
 func (h *Histogram) Value() map[string][]float64 {
 	root := h.root
 	if root == nil {
@@ -110,8 +261,180 @@ func (h *Histogram) Value() map[string][]float64 {
 
 	rv := map[string][]float64{}
 
-	for k, v := range root.value {
-		rv[k] = v
+	for k, s := range root.value {
+		// Always copy the observations so the caller never shares a backing
+		// array with a series that is still being appended to.
+		label := joinValues(root.labels[k], root.delimiter)
+		s.read(func(o *observations) {
+			values := make([]float64, 0, len(rv[label])+len(o.values))
+			values = append(values, rv[label]...)
+			rv[label] = append(values, o.values...)
+		})
+	}
+	return rv
+}
+
+// Samples returns the retained observations of each series in the histogram.
+func (h *Histogram) Samples() []HistogramSample {
+	root := h.root
+	if root == nil {
+		root = h
 	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	if len(root.value) == 0 {
+		return nil
+	}
+
+	rv := make([]HistogramSample, 0, len(root.value))
+	for k, s := range root.value {
+		var values []float64
+		s.read(func(o *observations) {
+			values = append([]float64(nil), o.values...)
+		})
+		rv = append(rv, HistogramSample{
+			Labels: toLabels(root.labels[k]),
+			Values: values,
+		})
+	}
+	sortHistogramSamples(rv)
+
 	return rv
 }
+
+// Get returns the retained observations of the series with exactly the
+// provided label name and value pairs, in any order.  If no series matches,
+// false is returned.  If the labels were passed to With in more than one
+// order, they match more than one series; the ambiguity is reported and false
+// is returned.
+func (h *Histogram) Get(labelValues ...string) ([]float64, bool) {
+	var rv []float64
+	ok := h.find(labelValues, func(o *observations) {
+		rv = append([]float64(nil), o.values...)
+	})
+
+	return rv, ok
+}
+
+// find calls fn with the series with exactly the provided label name and value
+// pairs, in any order, while holding the series lock.  If no series matches,
+// or the labels match more than one series, false is returned.
+func (h *Histogram) find(labelValues []string, fn func(*observations)) bool {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	s, err := h.lookup(labelValues)
+	if err != nil {
+		root.panic(root.meta.wrap(err))
+		return false
+	}
+	if s == nil {
+		return false
+	}
+
+	s.read(fn)
+	return true
+}
+
+// lookup returns the series with exactly the provided label name and value
+// pairs, in any order, or nil if no series matches.
+func (h *Histogram) lookup(labelValues []string) (*histogramSeries, error) {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	want, err := convert(labelValues)
+	if err != nil {
+		return nil, nil
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	k, ok, err := findLabels(root.labels, want)
+	if !ok {
+		return nil, err
+	}
+
+	return root.value[k], nil
+}
+
+// Events returns the events recorded by the tree of histograms.  Events are only
+// recorded when RecordEvents or RecordCallers is used.
+func (h *Histogram) Events() []Event {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return root.events.events()
+}
+
+// Snapshot returns an immutable copy of all of the series of the histogram.
+func (h *Histogram) Snapshot() *Snapshot {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	series := make([]snapshotSeries, 0, len(root.value))
+	for k, s := range root.value {
+		var obs *observations
+		s.read(func(o *observations) {
+			obs = o.clone()
+		})
+		series = append(series, snapshotSeries{
+			key:    k,
+			labels: root.labels[k],
+			obs:    obs,
+		})
+	}
+
+	return newSnapshot(KindHistogram, root.resets, series)
+}
+
+// Reset discards all of the series and events recorded by the tree of
+// histograms.  Options and expectations are kept.
+func (h *Histogram) Reset() {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	for k, s := range root.value {
+		s.discard()
+		root.index.Delete(k)
+	}
+	root.value = nil
+	root.labels = nil
+	root.resets++
+	root.events.list = nil
+	root.changed.notify()
+}
+
+// bucketBounds returns the bucket upper bounds of the tree of histograms.
+func (h *Histogram) bucketBounds() []float64 {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	if root.buckets == nil {
+		return DefaultBuckets
+	}
+	return root.buckets
+}
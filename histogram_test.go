@@ -4,6 +4,8 @@
 package mockitmetrics
 
 import (
+	"regexp"
+	"sync"
 	"testing"
 
 	kit "github.com/go-kit/kit/metrics"
@@ -145,6 +147,171 @@ func TestHistogram(t *testing.T) {
 			},
 			opt:         ExpectLabels("one", "two"),
 			expectPanic: true,
+		}, {
+			description: "values containing the delimiter do not collide",
+			fn: func(h kit.Histogram) {
+				h.With("a", "x.y").Observe(1)
+				h.With("a", "x", "b", "y").Observe(1)
+				h.With("a", `x\y`).Observe(1)
+			},
+			expected: map[string][]float64{
+				`x\.y`: {1.0},
+				"x.y":  {1.0},
+				`x\\y`: {1.0},
+			},
+		}, {
+			description: "reject values containing the delimiter",
+			fn: func(h kit.Histogram) {
+				h.With("a", "x.y")
+			},
+			opt:         RejectDelimiter(),
+			expectPanic: true,
+		}, {
+			description: "reject values containing a custom delimiter",
+			fn: func(h kit.Histogram) {
+				h.With("a", "x.y").Observe(1)
+				h.With("a", "x-y")
+			},
+			opts:        []Option{RejectDelimiter(), Delimiter("-")},
+			expectPanic: true,
+		}, {
+			description: "allowed label values",
+			fn: func(h kit.Histogram) {
+				h.With("code", "200", "method", "GET").Observe(1)
+				h.With("code", "500").With("method", "PUT").Observe(1)
+			},
+			opts: []Option{
+				ExpectLabelValues("code", "200", "404"),
+				ExpectLabelValues("code", "500"),
+				ExpectLabelPattern("method", regexp.MustCompile("^(GET|PUT)$")),
+			},
+			expected: map[string][]float64{
+				"200.GET": {1.0},
+				"500.PUT": {1.0},
+			},
+		}, {
+			description: "error when a label value is not allowed",
+			fn: func(h kit.Histogram) {
+				h.With("code", "201")
+			},
+			opt:         ExpectLabelValues("code", "200", "404"),
+			expectPanic: true,
+		}, {
+			description: "error when a label value does not match the pattern",
+			fn: func(h kit.Histogram) {
+				h.With("code", "200").With("path", "/users/1234")
+			},
+			opts: []Option{
+				ExpectLabelPattern("path", regexp.MustCompile("^/[a-z]+$")),
+				ExpectLabelValues("code", "200"),
+			},
+			expectPanic: true,
+		}, {
+			description: "labels in any order share a series",
+			fn: func(h kit.Histogram) {
+				h.With("b", "2", "a", "1").Observe(1)
+				h.With("a", "1").With("b", "2").Observe(1)
+				h.With("b", "3").With("a", "1").Observe(1)
+			},
+			opts: []Option{ExpectLabels("a", "b"), AnyLabelOrder()},
+			expected: map[string][]float64{
+				"1.2": {1.0, 1.0},
+				"1.3": {1.0},
+			},
+		}, {
+			description: "labels in any order are sorted by name without expected labels",
+			fn: func(h kit.Histogram) {
+				h.With("b", "2", "a", "1").Observe(1)
+				h.With("a", "1", "b", "2").Observe(1)
+			},
+			opt: AnyLabelOrder(),
+			expected: map[string][]float64{
+				"1.2": {1.0, 1.0},
+			},
+		}, {
+			description: "labels in any order must still be expected",
+			fn: func(h kit.Histogram) {
+				h.With("b", "2", "c", "1")
+			},
+			opts:        []Option{ExpectLabels("a", "b"), AnyLabelOrder()},
+			expectPanic: true,
+		}, {
+			description: "labels in any order must all be present",
+			fn: func(h kit.Histogram) {
+				h.With("b", "2").Observe(1)
+			},
+			opts:        []Option{ExpectLabels("a", "b"), AnyLabelOrder()},
+			expectPanic: true,
+		}, {
+			description: "error when labels are out of order",
+			fn: func(h kit.Histogram) {
+				h.With("b", "2", "a", "1")
+			},
+			opt:         ExpectLabels("a", "b"),
+			expectPanic: true,
+		}, {
+			description: "error when a label is repeated",
+			fn: func(h kit.Histogram) {
+				h.With("a", "1").With("a", "2")
+			},
+			expectPanic: true,
+		}, {
+			description: "error when a label is repeated in the same call",
+			fn: func(h kit.Histogram) {
+				h.With("a", "1", "a", "2")
+			},
+			opt:         OnDuplicateLabel(DuplicateFail),
+			expectPanic: true,
+		}, {
+			description: "the last value of a repeated label wins",
+			fn: func(h kit.Histogram) {
+				h.With("a", "1", "b", "2").With("a", "3").Observe(1)
+				h.With("a", "3", "b", "2").Observe(1)
+			},
+			opt: OnDuplicateLabel(DuplicateLastWins),
+			expected: map[string][]float64{
+				"3.2": {1.0, 1.0},
+			},
+		}, {
+			description: "the first value of a repeated label wins",
+			fn: func(h kit.Histogram) {
+				h.With("a", "1", "b", "2").With("a", "3").Observe(1)
+				h.With("a", "1").With("b", "2", "b", "4").Observe(1)
+			},
+			opt: OnDuplicateLabel(DuplicateFirstWins),
+			expected: map[string][]float64{
+				"1.2": {1.0, 1.0},
+			},
+		}, {
+			description: "valid Prometheus labels",
+			fn: func(h kit.Histogram) {
+				h.With("http_code", "200", "_method", "GÉT").Observe(1)
+			},
+			opt: PrometheusStrict(),
+			expected: map[string][]float64{
+				"200.GÉT": {1.0},
+			},
+		}, {
+			description: "error when a label is not a valid Prometheus label",
+			fn: func(h kit.Histogram) {
+				h.With("http-code", "200")
+			},
+			opt:         PrometheusStrict(),
+			expectPanic: true,
+		}, {
+			description: "error when a label uses the reserved prefix",
+			fn: func(h kit.Histogram) {
+				h.With("code", "200").With("__name__", "x")
+			},
+			opt:         PrometheusStrict(),
+			expectPanic: true,
+		}, {
+			description: "error when a value is not valid UTF-8",
+			fn: func(h kit.Histogram) {
+				h.With("code", "\xff")
+			},
+			opt:         PrometheusStrict(),
+			expectPanic: true,
 		},
 	}
 
@@ -164,3 +331,88 @@ func TestHistogram(t *testing.T) {
 		})
 	}
 }
+
+func TestHistogramSamples(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewHistogram()
+	assert.Nil(h.Samples())
+
+	h.With("route", "/x", "code", "200").Observe(1)
+	h.With("route", "/x", "code", "200").Observe(2)
+	h.With("route", "/y", "code", "200").Observe(3)
+
+	assert.Equal([]HistogramSample{
+		{
+			Labels: []Label{{Name: "route", Value: "/x"}, {Name: "code", Value: "200"}},
+			Values: []float64{1, 2},
+		}, {
+			Labels: []Label{{Name: "route", Value: "/y"}, {Name: "code", Value: "200"}},
+			Values: []float64{3},
+		},
+	}, h.Samples())
+
+	got, ok := h.Get("code", "200", "route", "/x")
+	assert.True(ok)
+	assert.Equal([]float64{1, 2}, got)
+
+	_, ok = h.Get("route", "/z", "code", "200")
+	assert.False(ok)
+}
+
+func TestHistogramValueIsACopy(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewHistogram()
+	h.Observe(1)
+	h.Observe(2)
+	h.Observe(3)
+
+	// Modifying or appending to the result must not affect the histogram, and
+	// later observations must not affect the result.
+	v := h.Value()[""]
+	v[0] = -1
+	v = append(v, -2)
+	h.Observe(4)
+
+	assert.Equal([]float64{-1, 2, 3, -2}, v)
+	assert.Equal(map[string][]float64{"": {1, 2, 3, 4}}, h.Value())
+
+	s := h.Samples()
+	s[0].Values[0] = -1
+	got, _ := h.Get()
+	got[1] = -1
+	assert.Equal(map[string][]float64{"": {1, 2, 3, 4}}, h.Value())
+}
+
+func TestHistogramValueRace(t *testing.T) {
+	h := NewHistogram()
+	series := h.With("route", "/x")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				series.Observe(1)
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i++ {
+		for _, v := range h.Value() {
+			for j := range v {
+				v[j] = -1
+			}
+		}
+	}
+	wg.Wait()
+
+	got, ok := h.Get("route", "/x")
+	assert.True(t, ok)
+	assert.Len(t, got, 4000)
+	for _, v := range got {
+		assert.Equal(t, 1.0, v)
+	}
+}
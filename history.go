@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+// gaugeHistory is the range of values, and optionally every value, of a single
+// gauge series.
+type gaugeHistory struct {
+	min    float64
+	max    float64
+	values []float64
+}
+
+// record tracks the new value of the series, returning the history to store.
+// The series lock must be held by the caller.
+func (h *gaugeHistory) record(value float64, retain bool) *gaugeHistory {
+	if h == nil {
+		h = &gaugeHistory{
+			min: value,
+			max: value,
+		}
+	}
+
+	if value < h.min {
+		h.min = value
+	}
+	if value > h.max {
+		h.max = value
+	}
+	if retain {
+		h.values = append(h.values, value)
+	}
+
+	return h
+}
+
+// History returns the value of the series with exactly the provided label name
+// and value pairs, in any order, after every Set and Add.  The history is only
+// retained when RecordHistory is used.  If no series matches, false is
+// returned.
+func (g *Gauge) History(labelValues ...string) ([]float64, bool) {
+	var rv []float64
+	ok := g.find(labelValues, func(h *gaugeHistory) {
+		rv = append([]float64(nil), h.values...)
+	})
+
+	return rv, ok
+}
+
+// Max returns the largest value the series with exactly the provided label
+// name and value pairs, in any order, has had.  If no series matches, false is
+// returned.
+func (g *Gauge) Max(labelValues ...string) (float64, bool) {
+	var rv float64
+	ok := g.find(labelValues, func(h *gaugeHistory) {
+		rv = h.max
+	})
+
+	return rv, ok
+}
+
+// Min returns the smallest value the series with exactly the provided label
+// name and value pairs, in any order, has had.  If no series matches, false is
+// returned.
+func (g *Gauge) Min(labelValues ...string) (float64, bool) {
+	var rv float64
+	ok := g.find(labelValues, func(h *gaugeHistory) {
+		rv = h.min
+	})
+
+	return rv, ok
+}
+
+// EverBelow returns true if the series with exactly the provided label name and
+// value pairs, in any order, has ever had a value below the threshold.
+func (g *Gauge) EverBelow(threshold float64, labelValues ...string) bool {
+	min, ok := g.Min(labelValues...)
+	return ok && min < threshold
+}
+
+// EverAbove returns true if the series with exactly the provided label name and
+// value pairs, in any order, has ever had a value above the threshold.
+func (g *Gauge) EverAbove(threshold float64, labelValues ...string) bool {
+	max, ok := g.Max(labelValues...)
+	return ok && max > threshold
+}
+
+// find calls fn with the history of the series with exactly the provided label
+// name and value pairs, in any order, while holding the series lock.  If no
+// series matches, or the labels match more than one series, false is returned.
+func (g *Gauge) find(labelValues []string, fn func(*gaugeHistory)) bool {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	s, err := g.lookup(labelValues)
+	if err != nil {
+		root.panic(root.meta.wrap(err))
+		return false
+	}
+	if s == nil {
+		return false
+	}
+
+	s.read(fn)
+	return true
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGaugeHistory(t *testing.T) {
+	tests := []struct {
+		description string
+		opt         Option
+		history     []float64
+	}{
+		{
+			description: "only the range is tracked by default",
+		}, {
+			description: "with the full history",
+			opt:         RecordHistory(),
+			history:     []float64{1, 2, 1, 0, -1, 0, 5},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			g := NewGauge(tc.opt)
+			inFlight := g.With("route", "/x")
+			inFlight.Add(1)
+			inFlight.Add(1)
+			inFlight.Add(-1)
+			inFlight.Add(-1)
+			inFlight.Add(-1)
+			inFlight.Add(1)
+			inFlight.Set(5)
+			g.With("route", "/y").Set(3)
+
+			history, ok := g.History("route", "/x")
+			assert.True(ok)
+			assert.Equal(tc.history, history)
+
+			min, ok := g.Min("route", "/x")
+			assert.True(ok)
+			assert.Equal(-1.0, min)
+
+			max, ok := g.Max("route", "/x")
+			assert.True(ok)
+			assert.Equal(5.0, max)
+
+			assert.True(g.EverBelow(0, "route", "/x"))
+			assert.False(g.EverBelow(-1, "route", "/x"))
+			assert.True(g.EverAbove(4, "route", "/x"))
+			assert.False(g.EverAbove(5, "route", "/x"))
+
+			min, _ = g.Min("route", "/y")
+			max, _ = g.Max("route", "/y")
+			assert.Equal(3.0, min)
+			assert.Equal(3.0, max)
+
+			_, ok = g.History("route", "/z")
+			assert.False(ok)
+			_, ok = g.Min("route", "/z")
+			assert.False(ok)
+			_, ok = g.Max("route", "/z")
+			assert.False(ok)
+			assert.False(g.EverBelow(100, "route", "/z"))
+			assert.False(g.EverAbove(-100, "route", "/z"))
+
+			g.Reset()
+			_, ok = g.Max("route", "/x")
+			assert.False(ok)
+		})
+	}
+}
@@ -6,7 +6,11 @@ package mockitmetrics
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 type tuple struct {
@@ -16,6 +20,26 @@ type tuple struct {
 
 var (
 	errInvalidLabelValues = errors.New("labelValues is invalid")
+
+	validLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// DuplicatePolicy selects what happens when a label is passed to With more
+// than once, either in the same call or across a chain of calls.
+type DuplicatePolicy int
+
+const (
+	// DuplicateFail treats a repeated label as a failure.  This is the
+	// default.
+	DuplicateFail DuplicatePolicy = iota
+
+	// DuplicateLastWins replaces the value of the label with the new value,
+	// keeping the label in its original position.
+	DuplicateLastWins
+
+	// DuplicateFirstWins keeps the original value of the label and ignores
+	// the new value.
+	DuplicateFirstWins
 )
 
 func convert(s []string) ([]tuple, error) {
@@ -41,17 +65,69 @@ func convert(s []string) ([]tuple, error) {
 	return rv, nil
 }
 
-func validateLabels(expected *[]string, actual []tuple, exact bool) error {
-	list := make([]string, 0, len(actual))
-	for _, t := range actual {
-		list = append(list, t.label)
+// mergeLabels returns the existing tuples followed by the added tuples, with
+// any repeated label handled according to the policy.  The existing tuples are
+// never modified.
+func mergeLabels(existing, added []tuple, policy DuplicatePolicy) ([]tuple, error) {
+	// The common case of a single call to With needs no copy.
+	if len(existing) == 0 && !hasDuplicates(added) {
+		return added, nil
+	}
+
+	rv := make([]tuple, 0, len(existing)+len(added))
+	rv = append(rv, existing...)
+
+	for _, t := range added {
+		i := indexLabel(rv, t.label)
+		if i < 0 {
+			rv = append(rv, t)
+			continue
+		}
+
+		switch policy {
+		case DuplicateLastWins:
+			rv[i] = t
+		case DuplicateFirstWins:
+		default:
+			return nil, fmt.Errorf("%w - the label '%s' is repeated: got '%s' and '%s'",
+				errInvalidLabelValues, t.label, rv[i].value, t.value)
+		}
+	}
+
+	return rv, nil
+}
+
+func hasDuplicates(t []tuple) bool {
+	for i := range t {
+		if indexLabel(t[:i], t[i].label) >= 0 {
+			return true
+		}
 	}
+	return false
+}
 
+// indexLabel returns the index of the label in the tuples, or -1 if it isn't
+// present.
+func indexLabel(t []tuple, label string) int {
+	for i, v := range t {
+		if v.label == label {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateLabels(expected *[]string, actual []tuple, exact bool) error {
 	// Only validate if expected is not nil.
 	if expected == nil {
 		return nil
 	}
 
+	list := make([]string, 0, len(actual))
+	for _, t := range actual {
+		list = append(list, t.label)
+	}
+
 	wanted := *expected
 
 	if exact && len(wanted) != len(actual) {
@@ -80,6 +156,186 @@ func validateLabels(expected *[]string, actual []tuple, exact bool) error {
 	return nil
 }
 
+// validateLabelSet is like validateLabels when exact is false, but the labels
+// may be in any order.
+func validateLabelSet(expected *[]string, actual []tuple) error {
+	if expected == nil {
+		return nil
+	}
+
+	wanted := *expected
+
+	list := make([]string, 0, len(actual))
+	for _, t := range actual {
+		list = append(list, t.label)
+	}
+
+	if len(wanted) < len(actual) {
+		return fmt.Errorf("%w - too many labels: want '%s', got '%s'",
+			errInvalidLabelValues,
+			strings.Join(wanted, "', '"),
+			strings.Join(list, "', '"))
+	}
+
+	for _, t := range actual {
+		found := false
+		for _, w := range wanted {
+			if w == t.label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w - the labels do not match: want '%s', got '%s'",
+				errInvalidLabelValues,
+				strings.Join(wanted, "', '"),
+				strings.Join(list, "', '"))
+		}
+	}
+
+	return nil
+}
+
+// orderLabels returns a copy of the tuples sorted into the order of the
+// expected labels.  Labels that aren't expected follow the expected ones,
+// sorted by name.  If no labels are expected, all of the labels are sorted by
+// name.
+func orderLabels(expected *[]string, t []tuple) []tuple {
+	rank := map[string]int{}
+	if expected != nil {
+		for i, label := range *expected {
+			if _, ok := rank[label]; !ok {
+				rank[label] = i
+			}
+		}
+	}
+
+	rv := append([]tuple(nil), t...)
+	sort.SliceStable(rv, func(i, j int) bool {
+		ri, iok := rank[rv[i].label]
+		rj, jok := rank[rv[j].label]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		}
+		return rv[i].label < rv[j].label
+	})
+
+	return rv
+}
+
+// checkDelimiter returns an error if any of the values contain the delimiter.
+func checkDelimiter(t []tuple, delimiter string) error {
+	if delimiter == "" {
+		return nil
+	}
+
+	for _, v := range t {
+		if strings.Contains(v.value, delimiter) {
+			return fmt.Errorf("%w - the value '%s' for label '%s' contains the delimiter '%s'",
+				errInvalidLabelValues, v.value, v.label, delimiter)
+		}
+	}
+
+	return nil
+}
+
+// checkPrometheus returns an error if any of the label names or values would
+// be rejected by Prometheus.
+func checkPrometheus(t []tuple) error {
+	for _, v := range t {
+		if !validLabelName.MatchString(v.label) {
+			return fmt.Errorf("%w - the label '%s' must match '%s'",
+				errInvalidLabelValues, v.label, validLabelName)
+		}
+		if strings.HasPrefix(v.label, "__") {
+			return fmt.Errorf("%w - the label '%s' must not start with '__', which is reserved",
+				errInvalidLabelValues, v.label)
+		}
+		if !utf8.ValidString(v.value) {
+			return fmt.Errorf("%w - the value %q for label '%s' is not valid UTF-8",
+				errInvalidLabelValues, v.value, v.label)
+		}
+	}
+
+	return nil
+}
+
+// valueRule restricts the values a label may have.
+type valueRule struct {
+	allowed  map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// addValueRule adds to the rule for the label, creating the rules as needed.
+func addValueRule(rules *map[string]*valueRule, label string, fn func(*valueRule)) {
+	if *rules == nil {
+		*rules = map[string]*valueRule{}
+	}
+	if (*rules)[label] == nil {
+		(*rules)[label] = &valueRule{}
+	}
+	fn((*rules)[label])
+}
+
+// checkValues returns an error if any of the values are not allowed by the
+// rule for their label.
+func checkValues(rules map[string]*valueRule, t []tuple) error {
+	for _, v := range t {
+		rule, ok := rules[v.label]
+		if !ok {
+			continue
+		}
+
+		if rule.allowed != nil && !rule.allowed[v.value] {
+			allowed := make([]string, 0, len(rule.allowed))
+			for a := range rule.allowed {
+				allowed = append(allowed, a)
+			}
+			sort.Strings(allowed)
+
+			return fmt.Errorf("%w - the value '%s' for label '%s' is not allowed: want one of '%s'",
+				errInvalidLabelValues, v.value, v.label, strings.Join(allowed, "', '"))
+		}
+
+		for _, re := range rule.patterns {
+			if !re.MatchString(v.value) {
+				return fmt.Errorf("%w - the value '%s' for label '%s' does not match the pattern '%s'",
+					errInvalidLabelValues, v.value, v.label, re)
+			}
+		}
+	}
+
+	return nil
+}
+
+// seriesKey returns a key that uniquely identifies the label names and values
+// of a series, regardless of the characters the values contain.  Each name and
+// value is prefixed by its length.
+func seriesKey(t []tuple) string {
+	n := 0
+	for _, v := range t {
+		n += len(v.label) + len(v.value) + 8
+	}
+
+	var b strings.Builder
+	b.Grow(n)
+	for _, v := range t {
+		b.WriteString(strconv.Itoa(len(v.label)))
+		b.WriteByte(':')
+		b.WriteString(v.label)
+		b.WriteString(strconv.Itoa(len(v.value)))
+		b.WriteByte(':')
+		b.WriteString(v.value)
+	}
+	return b.String()
+}
+
+// joinValues joins the values using the delimiter.  Any backslash or delimiter
+// found in a value is escaped with a backslash so distinct values never produce
+// the same string.
 func joinValues(t []tuple, delimiter string) string {
 	if len(t) == 0 {
 		return ""
@@ -87,8 +343,21 @@ func joinValues(t []tuple, delimiter string) string {
 
 	rv := make([]string, 0, len(t))
 	for _, v := range t {
-		rv = append(rv, v.value)
+		rv = append(rv, escapeValue(v.value, delimiter))
 	}
 
 	return strings.Join(rv, delimiter)
 }
+
+func escapeValue(s, delimiter string) string {
+	if !strings.Contains(s, `\`) && (delimiter == "" || !strings.Contains(s, delimiter)) {
+		return s
+	}
+
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	if delimiter != "" {
+		s = strings.ReplaceAll(s, delimiter, `\`+delimiter)
+	}
+
+	return s
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	errInvalidName = errors.New("metric name is invalid")
+
+	validName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+)
+
+// Metadata describes a metric.  It is set using the Name, Help and Unit
+// options.
+type Metadata struct {
+	// Name is the name of the metric.
+	Name string
+
+	// Help is the description of the metric.
+	Help string
+
+	// Unit is the unit of the values of the metric, like "seconds".
+	Unit string
+}
+
+// validateName returns an error if the name isn't a valid Prometheus metric
+// name.  An empty name is allowed since the name is optional.
+func validateName(name string) error {
+	if name == "" || validName.MatchString(name) {
+		return nil
+	}
+
+	return fmt.Errorf("%w - '%s' must match '%s'", errInvalidName, name, validName)
+}
+
+// wrap adds the name of the metric to the error, if it has one.
+func (m Metadata) wrap(err error) error {
+	if m.Name == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", m.Name, err)
+}
+
+// describe renders the kind, name and unit of the metric like
+// "histogram request_duration (seconds)".
+func (m Metadata) describe(kind Kind) string {
+	rv := kind.String()
+	if m.Name != "" {
+		rv += " " + m.Name
+	}
+	if m.Unit != "" {
+		rv += " (" + m.Unit + ")"
+	}
+	return rv
+}
+
+// describeSnapshot renders the metric and all of its series on a single line.
+func describeSnapshot(m Metadata, s *Snapshot) string {
+	if len(s.series) == 0 {
+		return m.describe(s.kind) + ": no series"
+	}
+
+	list := make([]string, 0, len(s.series))
+	for _, v := range s.series {
+		if v.obs != nil {
+			list = append(list, fmt.Sprintf("%s count %d sum %g",
+				formatLabels(v.labels), v.obs.count, v.obs.sum))
+			continue
+		}
+		list = append(list, fmt.Sprintf("%s %g", formatLabels(v.labels), v.value))
+	}
+
+	return m.describe(s.kind) + ": " + strings.Join(list, ", ")
+}
+
+// Metadata returns the name, help and unit of the counter.
+func (c *Counter) Metadata() Metadata {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	return root.meta
+}
+
+// String describes the counter and the value of each of its series, like
+// `counter http_requests_total: {code="200"} 3, {code="500"} 1`.
+func (c *Counter) String() string {
+	return describeSnapshot(c.Metadata(), c.Snapshot())
+}
+
+// Metadata returns the name, help and unit of the gauge.
+func (g *Gauge) Metadata() Metadata {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	return root.meta
+}
+
+// String describes the gauge and the value of each of its series, like
+// `gauge queue_length: {queue="a"} 3`.
+func (g *Gauge) String() string {
+	return describeSnapshot(g.Metadata(), g.Snapshot())
+}
+
+// Metadata returns the name, help and unit of the histogram.
+func (h *Histogram) Metadata() Metadata {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	return root.meta
+}
+
+// String describes the histogram and the count and sum of each of its series,
+// like `histogram request_duration (seconds): {route="/x"} count 2 sum 3.5`.
+func (h *Histogram) String() string {
+	return describeSnapshot(h.Metadata(), h.Snapshot())
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		name        string
+		expectPanic bool
+	}{
+		{name: ""},
+		{name: "http_requests_total"},
+		{name: "job:http_requests:rate5m"},
+		{name: "_private"},
+		{name: "http-requests", expectPanic: true},
+		{name: "1xx_responses", expectPanic: true},
+		{name: "requests total", expectPanic: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			for _, fn := range []func(){
+				func() { NewCounter(Name(tc.name)) },
+				func() { NewGauge(Name(tc.name)) },
+				func() { NewHistogram(Name(tc.name)) },
+			} {
+				if tc.expectPanic {
+					assert.Panics(fn)
+				} else {
+					assert.NotPanics(fn)
+				}
+			}
+		})
+	}
+}
+
+func TestNameFailureMarksHelper(t *testing.T) {
+	assert := assert.New(t)
+
+	tb := &fakeTB{TB: t}
+	NewCounter(WithT(tb), Name("http-requests"))
+	NewGauge(WithT(tb), Name("http-requests"))
+	NewHistogram(WithT(tb), Name("http-requests"))
+
+	assert.Len(tb.Errors(), 3)
+	assert.True(tb.marked("NewCounter"))
+	assert.True(tb.marked("NewGauge"))
+	assert.True(tb.marked("NewHistogram"))
+}
+
+func TestMetadata(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewCounter(Name("requests_total"), Help("The number of requests."), Unit("requests"))
+	expected := Metadata{
+		Name: "requests_total",
+		Help: "The number of requests.",
+		Unit: "requests",
+	}
+	assert.Equal(expected, c.Metadata())
+	assert.Equal(expected, c.With("code", "200").(*Counter).Metadata())
+
+	p := NewProvider()
+	p.NewGauge("queue.length")
+	assert.Equal(Metadata{Name: "queue.length"}, p.Gauge("queue.length").Metadata())
+}
+
+func TestMetadataErrors(t *testing.T) {
+	tb := &fakeTB{TB: t}
+
+	NewCounter(WithT(tb), Name("http-requests"))
+
+	c := NewCounter(WithT(tb), Name("requests_total"), ExpectLabels("code"), MaxSeries(1))
+	c.With("method", "GET")
+	c.Add(1)
+	c.With("code", "200").Add(1)
+	c.With("code", "500").Add(1)
+	c.Expect().Never()
+
+	tb.finish()
+
+	assert.Equal(t, []string{
+		"metric name is invalid - 'http-requests' must match '^[a-zA-Z_:][a-zA-Z0-9_:]*$'",
+		"requests_total: labelValues is invalid - the labels do not match: want 'code', got 'method'",
+		"requests_total: labelValues is invalid - expected labels: want 'code', got ''",
+		`requests_total: too many series - adding series {code="500"} would exceed the limit of 1 series`,
+		"requests_total: expectation not met - counter {}: want 0 calls to Add, got 1",
+	}, tb.Errors())
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		description string
+		metric      func() any
+		expected    string
+	}{
+		{
+			description: "an empty counter",
+			metric:      func() any { return NewCounter() },
+			expected:    "counter: no series",
+		}, {
+			description: "a counter",
+			metric: func() any {
+				c := NewCounter(Name("requests_total"))
+				c.With("code", "500").Add(1)
+				c.With("code", "200").Add(3)
+				return c
+			},
+			expected: `counter requests_total: {code="200"} 3, {code="500"} 1`,
+		}, {
+			description: "a gauge",
+			metric: func() any {
+				g := NewGauge(Name("queue_length"), Unit("items"))
+				g.With("queue", "a").Set(2.5)
+				return g
+			},
+			expected: `gauge queue_length (items): {queue="a"} 2.5`,
+		}, {
+			description: "a histogram",
+			metric: func() any {
+				h := NewHistogram(Name("request_duration"), Unit("seconds"))
+				child := h.With("route", "/x")
+				child.Observe(1.5)
+				child.Observe(2)
+				return h
+			},
+			expected: `histogram request_duration (seconds): {route="/x"} count 2 sum 3.5`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.metric().(fmt.Stringer).String())
+		})
+	}
+}
@@ -3,6 +3,13 @@
 
 package mockitmetrics
 
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+	"time"
+)
+
 const (
 	DelimiterDefault = "."
 	NoLabelDefault   = "none"
@@ -33,6 +40,241 @@ func (d delimiter) histogramApply(h *Histogram) {
 	h.delimiter = string(d)
 }
 
+// ExpectLabelValues restricts the values the label may have to the provided
+// values.  Using it more than once for the same label allows the values of
+// every use.  A value that isn't allowed is a failure when passed to With.
+func ExpectLabelValues(label string, values ...string) Option {
+	return expectLabelValues{label: label, values: values}
+}
+
+type expectLabelValues struct {
+	label  string
+	values []string
+}
+
+func (e expectLabelValues) apply(r *valueRule) {
+	if r.allowed == nil {
+		r.allowed = map[string]bool{}
+	}
+	for _, v := range e.values {
+		r.allowed[v] = true
+	}
+}
+
+func (e expectLabelValues) counterApply(c *Counter) {
+	addValueRule(&c.valueRules, e.label, e.apply)
+}
+
+func (e expectLabelValues) gaugeApply(g *Gauge) {
+	addValueRule(&g.valueRules, e.label, e.apply)
+}
+
+func (e expectLabelValues) histogramApply(h *Histogram) {
+	addValueRule(&h.valueRules, e.label, e.apply)
+}
+
+// ExpectLabelPattern requires the values of the label to match the regular
+// expression.  Using it more than once for the same label requires the values
+// to match every pattern.  A value that doesn't match is a failure when passed
+// to With.
+//
+// The pattern isn't anchored, use ^ and $ to match the whole value.
+func ExpectLabelPattern(label string, pattern *regexp.Regexp) Option {
+	return expectLabelPattern{label: label, pattern: pattern}
+}
+
+type expectLabelPattern struct {
+	label   string
+	pattern *regexp.Regexp
+}
+
+func (e expectLabelPattern) apply(r *valueRule) {
+	r.patterns = append(r.patterns, e.pattern)
+}
+
+func (e expectLabelPattern) counterApply(c *Counter) {
+	addValueRule(&c.valueRules, e.label, e.apply)
+}
+
+func (e expectLabelPattern) gaugeApply(g *Gauge) {
+	addValueRule(&g.valueRules, e.label, e.apply)
+}
+
+func (e expectLabelPattern) histogramApply(h *Histogram) {
+	addValueRule(&h.valueRules, e.label, e.apply)
+}
+
+// MaxSeries limits the number of distinct label combinations, or series, a
+// tree of metrics may have.  Updating a new series beyond the limit is a
+// failure.  Use Cardinality to find the labels responsible.
+func MaxSeries(n int) Option {
+	return maxSeries(n)
+}
+
+type maxSeries int
+
+func (m maxSeries) counterApply(c *Counter) {
+	c.maxSeries = int(m)
+}
+
+func (m maxSeries) gaugeApply(g *Gauge) {
+	g.maxSeries = int(m)
+}
+
+func (m maxSeries) histogramApply(h *Histogram) {
+	h.maxSeries = int(m)
+}
+
+// RejectDelimiter causes any label value containing the delimiter to be
+// treated as a failure.
+//
+// Series are always stored by their full label names and values, so a value
+// containing the delimiter never merges with another series.  The keys
+// returned by Value() escape the delimiter with a backslash instead.  This
+// option is for code that should never produce such values in the first place.
+func RejectDelimiter() Option {
+	return rejectDelimiter{}
+}
+
+type rejectDelimiter struct{}
+
+func (rejectDelimiter) counterApply(c *Counter) {
+	c.rejectDelimiter = true
+}
+
+func (rejectDelimiter) gaugeApply(g *Gauge) {
+	g.rejectDelimiter = true
+}
+
+func (rejectDelimiter) histogramApply(h *Histogram) {
+	h.rejectDelimiter = true
+}
+
+// PrometheusStrict rejects the label names and values that Prometheus would
+// reject: label names must match [a-zA-Z_][a-zA-Z0-9_]* and must not start
+// with the reserved "__" prefix, and values must be valid UTF-8.  Metrics
+// created by a Provider also have the name they are created with checked.
+func PrometheusStrict() Option {
+	return prometheusStrict{}
+}
+
+type prometheusStrict struct{}
+
+func (prometheusStrict) counterApply(c *Counter) {
+	c.strict = true
+}
+
+func (prometheusStrict) gaugeApply(g *Gauge) {
+	g.strict = true
+}
+
+func (prometheusStrict) histogramApply(h *Histogram) {
+	h.strict = true
+}
+
+// AnyLabelOrder treats the labels passed to With as a set instead of a list.
+// The labels are sorted into the order given to ExpectLabels, or by name if
+// ExpectLabels isn't used, before they are validated and stored.  Updates made
+// using the same labels in a different order go to the same series.
+func AnyLabelOrder() Option {
+	return anyLabelOrder{}
+}
+
+type anyLabelOrder struct{}
+
+func (anyLabelOrder) counterApply(c *Counter) {
+	c.anyLabelOrder = true
+}
+
+func (anyLabelOrder) gaugeApply(g *Gauge) {
+	g.anyLabelOrder = true
+}
+
+func (anyLabelOrder) histogramApply(h *Histogram) {
+	h.anyLabelOrder = true
+}
+
+// OnDuplicateLabel sets what happens when a label is passed to With more than
+// once.  The default is DuplicateFail.
+func OnDuplicateLabel(policy DuplicatePolicy) Option {
+	return onDuplicateLabel(policy)
+}
+
+type onDuplicateLabel DuplicatePolicy
+
+func (d onDuplicateLabel) counterApply(c *Counter) {
+	c.duplicates = DuplicatePolicy(d)
+}
+
+func (d onDuplicateLabel) gaugeApply(g *Gauge) {
+	g.duplicates = DuplicatePolicy(d)
+}
+
+func (d onDuplicateLabel) histogramApply(h *Histogram) {
+	h.duplicates = DuplicatePolicy(d)
+}
+
+// Name sets the name of the metric, which is included in failures and used by
+// String and WriteExposition.  A name that isn't a valid Prometheus metric
+// name is a failure.
+func Name(name string) Option {
+	return metricName(name)
+}
+
+type metricName string
+
+func (n metricName) counterApply(c *Counter) {
+	c.meta.Name = string(n)
+}
+
+func (n metricName) gaugeApply(g *Gauge) {
+	g.meta.Name = string(n)
+}
+
+func (n metricName) histogramApply(h *Histogram) {
+	h.meta.Name = string(n)
+}
+
+// Help sets the description of the metric, which is written as the HELP line
+// by WriteExposition.
+func Help(help string) Option {
+	return metricHelp(help)
+}
+
+type metricHelp string
+
+func (m metricHelp) counterApply(c *Counter) {
+	c.meta.Help = string(m)
+}
+
+func (m metricHelp) gaugeApply(g *Gauge) {
+	g.meta.Help = string(m)
+}
+
+func (m metricHelp) histogramApply(h *Histogram) {
+	h.meta.Help = string(m)
+}
+
+// Unit sets the unit of the values of the metric, like "seconds".  The unit is
+// included in the output of String.
+func Unit(unit string) Option {
+	return metricUnit(unit)
+}
+
+type metricUnit string
+
+func (u metricUnit) counterApply(c *Counter) {
+	c.meta.Unit = string(u)
+}
+
+func (u metricUnit) gaugeApply(g *Gauge) {
+	g.meta.Unit = string(u)
+}
+
+func (u metricUnit) histogramApply(h *Histogram) {
+	h.meta.Unit = string(u)
+}
+
 // PanicFunc sets the function to call when panic() would be called.
 func PanicFunc(f func(any)) Option {
 	return panicFunc(f)
@@ -78,3 +320,254 @@ func (e expectLabels) gaugeApply(g *Gauge) {
 func (e expectLabels) histogramApply(h *Histogram) {
 	h.expectedLabels = &e.labels
 }
+
+// WithT routes failures to the provided testing.TB instead of panicking.
+//
+// Failures are reported using t.Errorf so the test continues and the failure
+// points at the caller's file and line instead of inside mockitmetrics.  A call
+// to With that fails returns a metric that discards all updates, so a bad call
+// in a goroutine doesn't crash the test binary.
+//
+// Any expectations set using Expect are verified when the test completes.
+func WithT(t testing.TB) Option {
+	return withT{t: t}
+}
+
+type withT struct {
+	t testing.TB
+}
+
+func (w withT) errorf() func(any) {
+	t := w.t
+	return func(a any) {
+		t.Helper()
+		t.Errorf("%v", a)
+	}
+}
+
+func (w withT) counterApply(c *Counter) {
+	c.t = w.t
+	c.panic = w.errorf()
+	w.t.Cleanup(func() {
+		for _, err := range c.verify() {
+			w.t.Errorf("%v", err)
+		}
+
+		c.m.Lock()
+		defer c.m.Unlock()
+		c.listeners.unsubscribeAll()
+	})
+}
+
+func (w withT) gaugeApply(g *Gauge) {
+	g.t = w.t
+	g.panic = w.errorf()
+	w.t.Cleanup(func() {
+		for _, err := range g.verify() {
+			w.t.Errorf("%v", err)
+		}
+
+		g.m.Lock()
+		defer g.m.Unlock()
+		g.listeners.unsubscribeAll()
+	})
+}
+
+func (w withT) histogramApply(h *Histogram) {
+	h.t = w.t
+	h.panic = w.errorf()
+	w.t.Cleanup(func() {
+		for _, err := range h.verify() {
+			w.t.Errorf("%v", err)
+		}
+
+		h.m.Lock()
+		defer h.m.Unlock()
+		h.listeners.unsubscribeAll()
+	})
+}
+
+// RecordEvents records every Add, Set and Observe as an Event, available from
+// Events().  The events are shared by the whole tree of metrics.
+func RecordEvents() Option {
+	return recordEvents{}
+}
+
+type recordEvents struct{}
+
+func (recordEvents) counterApply(c *Counter) {
+	c.events.record = true
+}
+
+func (recordEvents) gaugeApply(g *Gauge) {
+	g.events.record = true
+}
+
+func (recordEvents) histogramApply(h *Histogram) {
+	h.events.record = true
+}
+
+// RecordCallers records events like RecordEvents, and also records the file
+// and line of the code that performed each operation.
+func RecordCallers() Option {
+	return recordCallers{}
+}
+
+type recordCallers struct{}
+
+func (recordCallers) counterApply(c *Counter) {
+	c.events.record = true
+	c.events.callers = true
+}
+
+func (recordCallers) gaugeApply(g *Gauge) {
+	g.events.record = true
+	g.events.callers = true
+}
+
+func (recordCallers) histogramApply(h *Histogram) {
+	h.events.record = true
+	h.events.callers = true
+}
+
+// TimeFunc sets the function used to get the current time.  The default is
+// time.Now.
+func TimeFunc(fn func() time.Time) Option {
+	return timeFunc(fn)
+}
+
+type timeFunc func() time.Time
+
+func (fn timeFunc) counterApply(c *Counter) {
+	c.events.now = fn
+}
+
+func (fn timeFunc) gaugeApply(g *Gauge) {
+	g.events.now = fn
+}
+
+func (fn timeFunc) histogramApply(h *Histogram) {
+	h.events.now = fn
+}
+
+// BucketBounds sets the upper bounds of the buckets used when a histogram is
+// exported using WriteExposition.  The bounds are sorted, and an implicit +Inf
+// bucket is always included, so passing no finite bounds leaves only the +Inf
+// bucket.  Counters and gauges ignore this option.
+func BucketBounds(bounds ...float64) Option {
+	return bucketBounds(sortBounds(bounds))
+}
+
+type bucketBounds []float64
+
+func (bucketBounds) counterApply(*Counter) {}
+
+func (bucketBounds) gaugeApply(*Gauge) {}
+
+func (b bucketBounds) histogramApply(h *Histogram) {
+	h.buckets = b
+}
+
+// QuantileInterpolation sets how the quantiles reported by Histogram.Stats are
+// interpolated.  The default is InterpolationLinear.  Counters and gauges
+// ignore this option.
+func QuantileInterpolation(m Interpolation) Option {
+	return quantileInterpolation(m)
+}
+
+type quantileInterpolation Interpolation
+
+func (quantileInterpolation) counterApply(*Counter) {}
+
+func (quantileInterpolation) gaugeApply(*Gauge) {}
+
+func (q quantileInterpolation) histogramApply(h *Histogram) {
+	h.interpolation = Interpolation(q)
+}
+
+// RetainAll stores every observation made by a histogram.  This is the
+// default, and gives exact results from every query.  Counters and gauges
+// ignore this option.
+func RetainAll() Option {
+	return storageOption{mode: storeAll}
+}
+
+// Reservoir bounds the memory used by a histogram by retaining a uniform
+// random sample of at most size observations per series.  The count, sum,
+// min, max, mean and standard deviation remain exact, while the quantiles and
+// bucket counts are estimated from the sample.  The sample is seeded the same
+// way every time so tests are repeatable.  Counters and gauges ignore this
+// option.
+func Reservoir(size int) Option {
+	if size < 1 {
+		size = 1
+	}
+	return storageOption{mode: storeReservoir, size: size}
+}
+
+// Streaming bounds the memory used by a histogram by retaining no
+// observations at all.  The count, sum, min, max, mean and standard deviation
+// remain exact, while the quantiles and bucket counts are estimated from a
+// sketch with 1% relative accuracy.  Queries that return observations, like
+// Value, return none.  Counters and gauges ignore this option.
+func Streaming() Option {
+	return storageOption{mode: storeStreaming}
+}
+
+type storageOption struct {
+	mode storageMode
+	size int
+}
+
+func (storageOption) counterApply(*Counter) {}
+
+func (storageOption) gaugeApply(*Gauge) {}
+
+func (s storageOption) histogramApply(h *Histogram) {
+	h.storage = storage{
+		mode: s.mode,
+		size: s.size,
+	}
+	if s.mode == storeReservoir {
+		h.storage.rand = rand.New(rand.NewSource(1)) //nolint:gosec
+	}
+}
+
+// RecordHistory retains the value of a gauge series after every Set and Add,
+// available from History.  The minimum and maximum values are always tracked,
+// this option is only needed for the full history.  Counters and histograms
+// ignore this option.
+func RecordHistory() Option {
+	return recordHistory{}
+}
+
+type recordHistory struct{}
+
+func (recordHistory) counterApply(*Counter) {}
+
+func (recordHistory) gaugeApply(g *Gauge) {
+	g.recordHistory = true
+}
+
+func (recordHistory) histogramApply(*Histogram) {}
+
+// OnUpdate calls the function after every Add, Set and Observe, with the root
+// lock released so the function is free to query the metric.  Updates made
+// concurrently may be delivered in any order.
+func OnUpdate(fn func(Update)) Option {
+	return onUpdate(fn)
+}
+
+type onUpdate func(Update)
+
+func (fn onUpdate) counterApply(c *Counter) {
+	c.listeners.hooks = append(c.listeners.hooks, fn)
+}
+
+func (fn onUpdate) gaugeApply(g *Gauge) {
+	g.listeners.hooks = append(g.listeners.hooks, fn)
+}
+
+func (fn onUpdate) histogramApply(h *Histogram) {
+	h.listeners.hooks = append(h.listeners.hooks, fn)
+}
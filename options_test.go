@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTB captures the failures reported to a testing.TB.
+type fakeTB struct {
+	testing.TB
+	m        sync.Mutex
+	errors   []string
+	cleanups []func()
+	helpers  []string
+}
+
+// Helper records the name of the function that called it, so tests can check
+// that failures are attributed to the caller.
+func (f *fakeTB) Helper() {
+	pc, _, _, _ := runtime.Caller(1)
+
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.helpers = append(f.helpers, runtime.FuncForPC(pc).Name())
+}
+
+// marked returns true if the function with the provided name called Helper.
+func (f *fakeTB) marked(name string) bool {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	for _, h := range f.helpers {
+		if strings.HasSuffix(h, "."+name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeTB) Cleanup(fn func()) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.cleanups = append(f.cleanups, fn)
+}
+
+// finish runs the cleanup functions the same way the testing package does.
+func (f *fakeTB) finish() {
+	f.m.Lock()
+	cleanups := f.cleanups
+	f.cleanups = nil
+	f.m.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Errors() []string {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return append([]string(nil), f.errors...)
+}
+
+func TestWithT(t *testing.T) {
+	tests := []struct {
+		description string
+		fn          func(*fakeTB)
+		errors      int
+	}{
+		{
+			description: "a valid counter reports nothing",
+			fn: func(tb *fakeTB) {
+				NewCounter(WithT(tb), ExpectLabels("one")).With("one", "1").Add(1)
+			},
+		}, {
+			description: "a counter with a negative delta",
+			fn: func(tb *fakeTB) {
+				NewCounter(WithT(tb)).Add(-1)
+			},
+			errors: 1,
+		}, {
+			description: "a counter with an invalid label is discarded",
+			fn: func(tb *fakeTB) {
+				c := NewCounter(WithT(tb), ExpectLabels("one"))
+				c.With("two", "2").With("one", "1").Add(1)
+				assert.Nil(t, c.Value())
+			},
+			errors: 1,
+		}, {
+			description: "a gauge with missing labels",
+			fn: func(tb *fakeTB) {
+				g := NewGauge(WithT(tb), ExpectLabels("one", "two"))
+				g.With("one", "1").Set(1)
+				g.With("one", "1").Add(1)
+			},
+			errors: 2,
+		}, {
+			description: "a gauge with an invalid label is discarded",
+			fn: func(tb *fakeTB) {
+				g := NewGauge(WithT(tb))
+				g.With("one").Set(1)
+				assert.Nil(t, g.Value())
+			},
+			errors: 1,
+		}, {
+			description: "a histogram with missing labels",
+			fn: func(tb *fakeTB) {
+				NewHistogram(WithT(tb), ExpectLabels("one")).Observe(1)
+			},
+			errors: 1,
+		}, {
+			description: "a histogram used from a goroutine",
+			fn: func(tb *fakeTB) {
+				h := NewHistogram(WithT(tb), ExpectLabels("one"))
+				var wg sync.WaitGroup
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						h.With("", "1").Observe(1)
+					}()
+				}
+				wg.Wait()
+			},
+			errors: 10,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			tb := &fakeTB{TB: t}
+
+			assert.NotPanics(t, func() { tc.fn(tb) })
+			assert.Len(t, tb.Errors(), tc.errors)
+		})
+	}
+}
+
+func TestLabelValueErrors(t *testing.T) {
+	tb := &fakeTB{TB: t}
+	c := NewCounter(WithT(tb),
+		ExpectLabelValues("code", "500", "200"),
+		ExpectLabelPattern("method", regexp.MustCompile("^[A-Z]+$")),
+	)
+
+	c.With("code", "201").Add(1)
+	c.With("code", "200", "method", "get").Add(1)
+
+	assert.Equal(t, []string{
+		"labelValues is invalid - the value '201' for label 'code' is not allowed: want one of '200', '500'",
+		"labelValues is invalid - the value 'get' for label 'method' does not match the pattern '^[A-Z]+$'",
+	}, tb.Errors())
+	assert.Nil(t, c.Value())
+}
+
+func TestDuplicateLabelErrors(t *testing.T) {
+	tb := &fakeTB{TB: t}
+	c := NewCounter(WithT(tb))
+
+	parent := c.With("code", "200", "method", "GET")
+	parent.With("code", "500").Add(1)
+	parent.With("path", "/").Add(1)
+
+	assert.Equal(t, []string{
+		"labelValues is invalid - the label 'code' is repeated: got '200' and '500'",
+	}, tb.Errors())
+	assert.Equal(t, map[string]float64{"200.GET./": 1}, c.Value())
+}
+
+func TestPrometheusStrictErrors(t *testing.T) {
+	tb := &fakeTB{TB: t}
+	p := NewProvider(WithT(tb), PrometheusStrict())
+
+	c := p.NewCounter("requests_total")
+	c.With("http-code", "200")
+	c.With("__code", "200")
+	c.With("code", "\xff")
+	c.With("code", "200").Add(1)
+
+	p.NewGauge("queue.length").Set(1)
+
+	assert.Equal(t, []string{
+		"requests_total: labelValues is invalid - the label 'http-code' must match '^[a-zA-Z_][a-zA-Z0-9_]*$'",
+		"requests_total: labelValues is invalid - the label '__code' must not start with '__', which is reserved",
+		`requests_total: labelValues is invalid - the value "\xff" for label 'code' is not valid UTF-8`,
+		"metric name is invalid - 'queue.length' must match '^[a-zA-Z_:][a-zA-Z0-9_:]*$'",
+	}, tb.Errors())
+	assert.Equal(t, []string{"requests_total"}, p.Names())
+}
@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	kit "github.com/go-kit/kit/metrics"
+)
+
+var (
+	errDuplicateName = errors.New("metric name is already registered")
+)
+
+// NewProvider creates a new provider that applies the provided options to
+// every metric it creates.
+func NewProvider(opts ...Option) *Provider {
+	return &Provider{
+		opts:       opts,
+		counters:   map[string]*Counter{},
+		gauges:     map[string]*Gauge{},
+		histograms: map[string]*Histogram{},
+	}
+}
+
+// Provider is a mock go-kit metrics provider.  It implements the
+// github.com/go-kit/kit/metrics/provider.Provider interface and remembers the
+// mock metrics it creates by name, so tests can inspect them afterwards.
+//
+// Asking for the same name and type more than once returns the same metric.
+// Asking for a name that is already registered as a different type is a
+// failure, reported the same way the metric reports any other failure.
+type Provider struct {
+	opts       []Option
+	m          sync.Mutex
+	counters   map[string]*Counter
+	gauges     map[string]*Gauge
+	histograms map[string]*Histogram
+}
+
+// NewCounter returns the counter with the provided name, creating it if
+// needed.
+func (p *Provider) NewCounter(name string) kit.Counter {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if c, ok := p.counters[name]; ok {
+		return c
+	}
+
+	c := NewCounter(p.opts...)
+	c.meta.Name = name
+	if c.t != nil {
+		c.t.Helper()
+	}
+
+	if c.strict {
+		if err := validateName(name); err != nil {
+			c.panic(err)
+			return c
+		}
+	}
+
+	if err := p.registered(name); err != nil {
+		c.panic(err)
+		return c
+	}
+
+	p.counters[name] = c
+	return c
+}
+
+// NewGauge returns the gauge with the provided name, creating it if needed.
+func (p *Provider) NewGauge(name string) kit.Gauge {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if g, ok := p.gauges[name]; ok {
+		return g
+	}
+
+	g := NewGauge(p.opts...)
+	g.meta.Name = name
+	if g.t != nil {
+		g.t.Helper()
+	}
+
+	if g.strict {
+		if err := validateName(name); err != nil {
+			g.panic(err)
+			return g
+		}
+	}
+
+	if err := p.registered(name); err != nil {
+		g.panic(err)
+		return g
+	}
+
+	p.gauges[name] = g
+	return g
+}
+
+// NewHistogram returns the histogram with the provided name, creating it if
+// needed.  The number of buckets is ignored; use BucketBounds and the storage
+// options instead.
+func (p *Provider) NewHistogram(name string, _ int) kit.Histogram {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if h, ok := p.histograms[name]; ok {
+		return h
+	}
+
+	h := NewHistogram(p.opts...)
+	h.meta.Name = name
+	if h.t != nil {
+		h.t.Helper()
+	}
+
+	if h.strict {
+		if err := validateName(name); err != nil {
+			h.panic(err)
+			return h
+		}
+	}
+
+	if err := p.registered(name); err != nil {
+		h.panic(err)
+		return h
+	}
+
+	p.histograms[name] = h
+	return h
+}
+
+// Stop is a no-op, it is present to satisfy the provider interface.
+func (p *Provider) Stop() {}
+
+// Counter returns the counter with the provided name, or nil if there isn't
+// one.
+func (p *Provider) Counter(name string) *Counter {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return p.counters[name]
+}
+
+// Gauge returns the gauge with the provided name, or nil if there isn't one.
+func (p *Provider) Gauge(name string) *Gauge {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return p.gauges[name]
+}
+
+// Histogram returns the histogram with the provided name, or nil if there
+// isn't one.
+func (p *Provider) Histogram(name string) *Histogram {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return p.histograms[name]
+}
+
+// Names returns the sorted names of all of the metrics created by the
+// provider.
+func (p *Provider) Names() []string {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	rv := make([]string, 0, len(p.counters)+len(p.gauges)+len(p.histograms))
+	for name := range p.counters {
+		rv = append(rv, name)
+	}
+	for name := range p.gauges {
+		rv = append(rv, name)
+	}
+	for name := range p.histograms {
+		rv = append(rv, name)
+	}
+	sort.Strings(rv)
+
+	return rv
+}
+
+// registered returns an error if the name is already used by any metric.  The
+// lock must be held by the caller.
+func (p *Provider) registered(name string) error {
+	kind := ""
+	if _, ok := p.counters[name]; ok {
+		kind = "counter"
+	}
+	if _, ok := p.gauges[name]; ok {
+		kind = "gauge"
+	}
+	if _, ok := p.histograms[name]; ok {
+		kind = "histogram"
+	}
+
+	if kind == "" {
+		return nil
+	}
+
+	return fmt.Errorf("%w - '%s' is a %s", errDuplicateName, name, kind)
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"testing"
+
+	kit "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// provider has the same method set as the
+// github.com/go-kit/kit/metrics/provider.Provider interface, which can't be
+// imported without pulling in every backend.
+type provider interface {
+	NewCounter(name string) kit.Counter
+	NewGauge(name string) kit.Gauge
+	NewHistogram(name string, buckets int) kit.Histogram
+	Stop()
+}
+
+var _ provider = (*Provider)(nil)
+
+func TestProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	p := NewProvider(ExpectLabels("code"))
+
+	p.NewCounter("requests_total").With("code", "200").Add(1)
+	p.NewCounter("requests_total").With("code", "200").Add(1)
+	p.NewGauge("in_flight").With("code", "200").Set(3)
+	p.NewHistogram("latency", 10).With("code", "200").Observe(0.5)
+	p.Stop()
+
+	assert.Equal(map[string]float64{"200": 2}, p.Counter("requests_total").Value())
+	assert.Equal(map[string]float64{"200": 3}, p.Gauge("in_flight").Value())
+	assert.Equal(map[string][]float64{"200": {0.5}}, p.Histogram("latency").Value())
+
+	assert.Nil(p.Counter("in_flight"))
+	assert.Nil(p.Gauge("latency"))
+	assert.Nil(p.Histogram("requests_total"))
+
+	// The default options are applied.
+	assert.Panics(func() { p.NewCounter("errors_total").Add(1) })
+}
+
+func TestProviderConflicts(t *testing.T) {
+	tests := []struct {
+		description string
+		fn          func(*Provider)
+	}{
+		{
+			description: "a gauge with the name of a counter",
+			fn: func(p *Provider) {
+				p.NewCounter("name")
+				p.NewGauge("name")
+			},
+		}, {
+			description: "a histogram with the name of a gauge",
+			fn: func(p *Provider) {
+				p.NewGauge("name")
+				p.NewHistogram("name", 0)
+			},
+		}, {
+			description: "a counter with the name of a histogram",
+			fn: func(p *Provider) {
+				p.NewHistogram("name", 0)
+				p.NewCounter("name")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Panics(func() { tc.fn(NewProvider()) })
+
+			tb := &fakeTB{TB: t}
+			p := NewProvider(WithT(tb))
+			tc.fn(p)
+			assert.Len(tb.Errors(), 1)
+		})
+	}
+}
+
+func TestProviderNames(t *testing.T) {
+	p := NewProvider()
+	assert.Empty(t, p.Names())
+
+	p.NewHistogram("c", 0)
+	p.NewGauge("b")
+	p.NewCounter("a")
+	p.NewCounter("a")
+
+	assert.Equal(t, []string{"a", "b", "c"}, p.Names())
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	errAmbiguousLabels = errors.New("labels match more than one series")
+)
+
+// Label is a single label name and value pair.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is the value of a single counter or gauge series.
+type Sample struct {
+	// Labels are the label name and value pairs in the order they were
+	// provided to With.
+	Labels []Label
+
+	// Value is the current value of the series.
+	Value float64
+}
+
+// HistogramSample is the set of observations of a single histogram series.
+type HistogramSample struct {
+	// Labels are the label name and value pairs in the order they were
+	// provided to With.
+	Labels []Label
+
+	// Values are the observations in the order they were made.
+	Values []float64
+}
+
+func toLabels(t []tuple) []Label {
+	rv := make([]Label, 0, len(t))
+	for _, v := range t {
+		rv = append(rv, Label{
+			Name:  v.label,
+			Value: v.value,
+		})
+	}
+	return rv
+}
+
+// sameLabels returns true if the two sets of tuples contain the same label
+// name and value pairs, regardless of order.
+func sameLabels(a, b []tuple) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, want := range a {
+		found := false
+		for _, got := range b {
+			if want == got {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findLabels returns the key of the series with exactly the wanted label name
+// and value pairs, in any order.  Unless AnyLabelOrder is used, series are
+// kept in the order their labels were passed to With, so the same labels in
+// two orders are two series; rather than picking one of them, an error is
+// returned.
+func findLabels(labels map[string][]tuple, want []tuple) (string, bool, error) {
+	var rv string
+	var matches int
+	for k, lvp := range labels {
+		if sameLabels(want, lvp) {
+			rv = k
+			matches++
+		}
+	}
+
+	if matches > 1 {
+		return "", false, fmt.Errorf("%w: %s matches %d series", errAmbiguousLabels, formatLabels(want), matches)
+	}
+
+	return rv, matches == 1, nil
+}
+
+// lessLabels orders label sets by their name and value pairs so the results
+// of Samples() are stable.
+func lessLabels(a, b []Label) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].Name != b[i].Name {
+			return a[i].Name < b[i].Name
+		}
+		if a[i].Value != b[i].Value {
+			return a[i].Value < b[i].Value
+		}
+	}
+	return len(a) < len(b)
+}
+
+func sortSamples(s []Sample) {
+	sort.Slice(s, func(i, j int) bool {
+		return lessLabels(s[i].Labels, s[j].Labels)
+	})
+}
+
+func sortHistogramSamples(s []HistogramSample) {
+	sort.Slice(s, func(i, j int) bool {
+		return lessLabels(s[i].Labels, s[j].Labels)
+	})
+}
+
+// containsLabels returns true if all of the wanted label name and value pairs
+// are present in the tuples.
+func containsLabels(t, want []tuple) bool {
+	for _, w := range want {
+		found := false
+		for _, v := range t {
+			if v == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatLabels renders the tuples like {code="500", method="GET"}.
+func formatLabels(t []tuple) string {
+	pairs := make([]string, 0, len(t))
+	for _, v := range t {
+		pairs = append(pairs, v.label+"="+strconv.Quote(v.value))
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
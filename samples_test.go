@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmbiguousLabels(t *testing.T) {
+	const ambiguous = `labels match more than one series: {a="1", b="2"} matches 2 series`
+
+	tb := &fakeTB{TB: t}
+
+	c := NewCounter(WithT(tb))
+	c.With("a", "1", "b", "2").Add(1)
+	c.With("b", "2", "a", "1").Add(5)
+
+	g := NewGauge(WithT(tb))
+	g.With("a", "1", "b", "2").Set(1)
+	g.With("b", "2", "a", "1").Set(5)
+
+	h := NewHistogram(WithT(tb))
+	h.With("a", "1", "b", "2").Observe(1)
+	h.With("b", "2", "a", "1").Observe(5)
+
+	for i := 0; i < 20; i++ {
+		_, ok := c.Get("a", "1", "b", "2")
+		assert.False(t, ok)
+	}
+
+	_, ok := g.Get("a", "1", "b", "2")
+	assert.False(t, ok)
+	_, ok = g.Max("a", "1", "b", "2")
+	assert.False(t, ok)
+
+	_, ok = h.Get("a", "1", "b", "2")
+	assert.False(t, ok)
+	_, ok = h.Stats("a", "1", "b", "2")
+	assert.False(t, ok)
+
+	errs := tb.Errors()
+	require.Len(t, errs, 24)
+	for _, err := range errs {
+		assert.Equal(t, ambiguous, err)
+	}
+
+	err := c.WaitUntilAtLeast(context.Background(), 1, "a", "1", "b", "2")
+	assert.ErrorIs(t, err, errAmbiguousLabels)
+	err = g.WaitUntilAtLeast(context.Background(), 1, "a", "1", "b", "2")
+	assert.ErrorIs(t, err, errAmbiguousLabels)
+	err = h.WaitUntilAtLeast(context.Background(), 1, "a", "1", "b", "2")
+	assert.ErrorIs(t, err, errAmbiguousLabels)
+
+	assert.Panics(t, func() {
+		c.Snapshot().Get("a", "1", "b", "2")
+	})
+	assert.Panics(t, func() {
+		h.Snapshot().Observations("b", "2", "a", "1")
+	})
+
+	// With AnyLabelOrder both orders are the same series.
+	c = NewCounter(AnyLabelOrder())
+	c.With("a", "1", "b", "2").Add(1)
+	c.With("b", "2", "a", "1").Add(5)
+	got, ok := c.Get("b", "2", "a", "1")
+	assert.True(t, ok)
+	assert.Equal(t, 6.0, got)
+}
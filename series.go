@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// The maps of series are protected by the root lock, but each series has its
+// own lock protecting its value.  The lock order is always the root lock, then
+// the series lock.
+//
+// The root also indexes the series by key in a sync.Map, so a metric returned
+// by With finds an existing series without the root lock, and remembers it for
+// later updates.  Updates only take the series lock, unless the series doesn't
+// exist yet or something needs every update of the tree to be ordered, such
+// as recording events or delivering updates to listeners.  Reset marks the
+// series it discards as stale so a metric that remembers one goes back to the
+// root.
+
+// counterSeries is the state of a single counter series.
+type counterSeries struct {
+	m     sync.Mutex
+	value float64
+	adds  int
+	stale bool
+}
+
+// add adds the delta to the series and returns the new value.  If the series
+// was discarded by Reset, false is returned and the series is unchanged.
+func (s *counterSeries) add(delta float64) (float64, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.stale {
+		return 0, false
+	}
+
+	s.value += delta
+	s.adds++
+	return s.value, true
+}
+
+// get returns the value of the series and the number of calls to Add.
+func (s *counterSeries) get() (float64, int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.value, s.adds
+}
+
+func (s *counterSeries) discard() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.stale = true
+}
+
+// gaugeSeries is the state of a single gauge series.
+type gaugeSeries struct {
+	m       sync.Mutex
+	value   float64
+	writes  *atomic.Uint64
+	written uint64
+	history *gaugeHistory
+	stale   bool
+}
+
+// update sets the series to the value, or adds the value to the series if
+// delta is true, and returns the new value.  If the series was discarded by
+// Reset, false is returned and the series is unchanged.
+func (s *gaugeSeries) update(value float64, delta, retain bool) (float64, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.stale {
+		return 0, false
+	}
+
+	if s.writes != nil {
+		s.written = s.writes.Add(1)
+	}
+
+	if delta {
+		s.value += value
+	} else {
+		s.value = value
+	}
+	s.history = s.history.record(s.value, retain)
+	return s.value, true
+}
+
+// get returns the value of the series.
+func (s *gaugeSeries) get() float64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.value
+}
+
+// order orders the later updates of the series using writes, which is shared
+// with the other series that have the same key in Value().
+func (s *gaugeSeries) order(writes *atomic.Uint64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.writes = writes
+}
+
+// latest returns the value of the series and when it was last written,
+// relative to the other series sharing its key in Value().  Series that were
+// never ordered report zero.
+func (s *gaugeSeries) latest() (float64, uint64) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.value, s.written
+}
+
+// read calls fn with the history of the series while holding the series lock.
+func (s *gaugeSeries) read(fn func(*gaugeHistory)) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	fn(s.history)
+}
+
+func (s *gaugeSeries) discard() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.stale = true
+}
+
+// histogramSeries is the state of a single histogram series.
+type histogramSeries struct {
+	m     sync.Mutex
+	obs   *observations
+	stale bool
+}
+
+// observe adds the value to the series and returns the number of
+// observations.  If the series was discarded by Reset, false is returned and
+// the series is unchanged.
+func (s *histogramSeries) observe(v float64) (uint64, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.stale {
+		return 0, false
+	}
+
+	s.obs.observe(v)
+	return s.obs.count, true
+}
+
+// read calls fn with the observations of the series while holding the series
+// lock.
+func (s *histogramSeries) read(fn func(*observations)) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	fn(s.obs)
+}
+
+func (s *histogramSeries) discard() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.stale = true
+}
@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	kit "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentUpdates(t *testing.T) {
+	const (
+		goroutines = 8
+		updates    = 1000
+	)
+
+	tests := []struct {
+		description string
+		opts        []Option
+	}{
+		{
+			description: "without listeners",
+		}, {
+			description: "with events",
+			opts:        []Option{RecordEvents()},
+		}, {
+			description: "with a hook",
+			opts:        []Option{OnUpdate(func(Update) {})},
+		}, {
+			description: "with reservoir storage",
+			opts:        []Option{Reservoir(10)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			c := NewCounter(tc.opts...)
+			g := NewGauge(tc.opts...)
+			h := NewHistogram(tc.opts...)
+
+			// Half of the goroutines share each series, and each reuses the
+			// child it got from With.
+			var wg sync.WaitGroup
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func(id string) {
+					defer wg.Done()
+					cc := c.With("id", id)
+					gc := g.With("id", id)
+					hc := h.With("id", id)
+					for j := 0; j < updates; j++ {
+						cc.Add(1)
+						gc.Add(1)
+						hc.Observe(1)
+					}
+				}(strconv.Itoa(i % 2))
+			}
+			wg.Wait()
+
+			total := float64(goroutines / 2 * updates)
+			assert.Equal(map[string]float64{"0": total, "1": total}, c.Value())
+			assert.Equal(map[string]float64{"0": total, "1": total}, g.Value())
+
+			stats, ok := h.Stats("id", "0")
+			assert.True(ok)
+			assert.Equal(uint64(total), stats.Count)
+		})
+	}
+}
+
+func TestResetDiscardsRememberedSeries(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewCounter()
+	g := NewGauge(RecordHistory())
+	h := NewHistogram()
+
+	cc := c.With("id", "1")
+	gc := g.With("id", "1")
+	hc := h.With("id", "1")
+
+	cc.Add(5)
+	gc.Set(5)
+	hc.Observe(5)
+
+	c.Reset()
+	g.Reset()
+	h.Reset()
+
+	cc.Add(1)
+	gc.Add(1)
+	hc.Observe(1)
+
+	assert.Equal(map[string]float64{"1": 1}, c.Value())
+	assert.Equal(map[string]float64{"1": 1}, g.Value())
+	assert.Equal(map[string][]float64{"1": {1}}, h.Value())
+
+	history, ok := g.History("id", "1")
+	assert.True(ok)
+	assert.Equal([]float64{1}, history)
+}
+
+func TestSubscribeAfterUpdates(t *testing.T) {
+	c := NewCounter()
+	child := c.With("id", "1")
+	child.Add(1)
+
+	ch := c.Subscribe()
+	defer c.Unsubscribe(ch)
+	child.Add(2)
+
+	u := <-ch
+	assert.Equal(t, 3.0, u.Value)
+}
+
+// parallelIDs gives each goroutine of a parallel benchmark its own label value.
+func parallelIDs() func() string {
+	var n atomic.Int64
+	return func() string {
+		return strconv.FormatInt(n.Add(1), 10)
+	}
+}
+
+func BenchmarkCounterAdd(b *testing.B) {
+	b.Run("same series", func(b *testing.B) {
+		c := NewCounter(ExpectLabels("code")).With("code", "200")
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				c.Add(1)
+			}
+		})
+	})
+
+	b.Run("series per goroutine", func(b *testing.B) {
+		c := NewCounter(ExpectLabels("id"))
+		next := parallelIDs()
+		b.RunParallel(func(pb *testing.PB) {
+			child := c.With("id", next())
+			for pb.Next() {
+				child.Add(1)
+			}
+		})
+	})
+
+	b.Run("with each update", func(b *testing.B) {
+		c := NewCounter(ExpectLabels("id"))
+		next := parallelIDs()
+		b.RunParallel(func(pb *testing.PB) {
+			id := next()
+			for pb.Next() {
+				c.With("id", id).Add(1)
+			}
+		})
+	})
+}
+
+func BenchmarkGaugeSet(b *testing.B) {
+	b.Run("same series", func(b *testing.B) {
+		g := NewGauge(ExpectLabels("queue")).With("queue", "a")
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				g.Set(1)
+			}
+		})
+	})
+
+	b.Run("series per goroutine", func(b *testing.B) {
+		g := NewGauge(ExpectLabels("id"))
+		next := parallelIDs()
+		b.RunParallel(func(pb *testing.PB) {
+			child := g.With("id", next())
+			for pb.Next() {
+				child.Set(1)
+			}
+		})
+	})
+
+	b.Run("with each update", func(b *testing.B) {
+		g := NewGauge(ExpectLabels("id"))
+		next := parallelIDs()
+		b.RunParallel(func(pb *testing.PB) {
+			id := next()
+			for pb.Next() {
+				g.With("id", id).Set(1)
+			}
+		})
+	})
+}
+
+func BenchmarkHistogramObserve(b *testing.B) {
+	benchmarks := []struct {
+		description string
+		opts        []Option
+	}{
+		{
+			description: "retain all",
+		}, {
+			description: "streaming",
+			opts:        []Option{Streaming()},
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.description, func(b *testing.B) {
+			h := NewHistogram(append(bm.opts, ExpectLabels("id"))...)
+			next := parallelIDs()
+			b.RunParallel(func(pb *testing.PB) {
+				var child kit.Histogram = h.With("id", next())
+				for pb.Next() {
+					child.Observe(1)
+				}
+			})
+		})
+	}
+}
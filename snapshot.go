@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Snapshot is an immutable copy of all of the series of a metric at a point
+// in time.
+type Snapshot struct {
+	kind       Kind
+	generation uint64
+	series     []snapshotSeries
+}
+
+type snapshotSeries struct {
+	key    string
+	labels []tuple
+	value  float64
+	obs    *observations
+}
+
+// newSnapshot creates a snapshot of the series.  The generation is the number
+// of times the metric had been reset when the snapshot was taken.
+func newSnapshot(kind Kind, generation uint64, series []snapshotSeries) *Snapshot {
+	sort.Slice(series, func(i, j int) bool {
+		return lessLabels(toLabels(series[i].labels), toLabels(series[j].labels))
+	})
+
+	return &Snapshot{
+		kind:       kind,
+		generation: generation,
+		series:     series,
+	}
+}
+
+// Kind returns the kind of metric the snapshot was taken of.
+func (s *Snapshot) Kind() Kind {
+	return s.kind
+}
+
+// Samples returns the value of each counter or gauge series.  Snapshots of a
+// histogram return nil; use HistogramSamples instead.
+func (s *Snapshot) Samples() []Sample {
+	if s.kind == KindHistogram || len(s.series) == 0 {
+		return nil
+	}
+
+	rv := make([]Sample, 0, len(s.series))
+	for _, v := range s.series {
+		rv = append(rv, Sample{
+			Labels: toLabels(v.labels),
+			Value:  v.value,
+		})
+	}
+	return rv
+}
+
+// HistogramSamples returns the observations of each histogram series.
+// Snapshots of a counter or gauge return nil; use Samples instead.
+func (s *Snapshot) HistogramSamples() []HistogramSample {
+	if s.kind != KindHistogram || len(s.series) == 0 {
+		return nil
+	}
+
+	rv := make([]HistogramSample, 0, len(s.series))
+	for _, v := range s.series {
+		rv = append(rv, HistogramSample{
+			Labels: toLabels(v.labels),
+			Values: append([]float64(nil), v.obs.values...),
+		})
+	}
+	return rv
+}
+
+// Get returns the value of the counter or gauge series with exactly the
+// provided label name and value pairs, in any order.  If no series matches,
+// false is returned.  Get panics if the labels match more than one series.
+func (s *Snapshot) Get(labelValues ...string) (float64, bool) {
+	if s.kind == KindHistogram {
+		return 0, false
+	}
+
+	v, ok := s.find(labelValues)
+	if !ok {
+		return 0, false
+	}
+	return v.value, true
+}
+
+// Observations returns the observations of the histogram series with exactly
+// the provided label name and value pairs, in any order.  If no series
+// matches, false is returned.  Observations panics if the labels match more
+// than one series.
+func (s *Snapshot) Observations(labelValues ...string) ([]float64, bool) {
+	if s.kind != KindHistogram {
+		return nil, false
+	}
+
+	v, ok := s.find(labelValues)
+	if !ok {
+		return nil, false
+	}
+	return append([]float64(nil), v.obs.values...), true
+}
+
+// find returns the series with exactly the provided label name and value
+// pairs, in any order.  If the labels were passed to With in more than one
+// order, they match more than one series and find panics.
+func (s *Snapshot) find(labelValues []string) (snapshotSeries, bool) {
+	want, err := convert(labelValues)
+	if err != nil {
+		return snapshotSeries{}, false
+	}
+
+	var rv snapshotSeries
+	var matches int
+	for _, v := range s.series {
+		if sameLabels(want, v.labels) {
+			rv = v
+			matches++
+		}
+	}
+
+	if matches > 1 {
+		panic(fmt.Errorf("%w: %s matches %d series", errAmbiguousLabels, formatLabels(want), matches))
+	}
+
+	return rv, matches == 1
+}
+
+// Delta returns the changes from the older snapshot to this one, as a new
+// snapshot of the same kind of metric.  Counter and gauge series hold the
+// difference in value, and histogram series hold the observations made since
+// the older snapshot.  Series that didn't change are left out.
+//
+// Histograms using Reservoir or Streaming storage only know how many
+// observations were made since the older snapshot and their sum, so their
+// delta series have no observations.
+//
+// A nil older snapshot is treated as empty, as is an older snapshot taken
+// before the metric was last reset, so each series holds its whole value.
+// Taking the delta of snapshots of different kinds of metrics panics.
+func (s *Snapshot) Delta(older *Snapshot) *Snapshot {
+	if older == nil {
+		older = &Snapshot{kind: s.kind}
+	}
+
+	if s.kind != older.kind {
+		panic(fmt.Sprintf("mockitmetrics: delta of a %s snapshot and a %s snapshot", s.kind, older.kind))
+	}
+
+	prev := make(map[string]snapshotSeries, len(older.series))
+	if older.generation == s.generation {
+		for _, v := range older.series {
+			prev[v.key] = v
+		}
+	}
+
+	series := make([]snapshotSeries, 0, len(s.series))
+	for _, v := range s.series {
+		p := prev[v.key]
+
+		d := snapshotSeries{
+			key:    v.key,
+			labels: v.labels,
+		}
+
+		if s.kind == KindHistogram {
+			d.obs = v.obs.since(p.obs)
+			if d.obs.count == 0 {
+				continue
+			}
+		} else {
+			d.value = v.value - p.value
+			if _, found := prev[v.key]; found && d.value == 0 {
+				continue
+			}
+		}
+
+		series = append(series, d)
+	}
+
+	return newSnapshot(s.kind, s.generation, series)
+}
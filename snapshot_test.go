@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterSnapshotDelta(t *testing.T) {
+	c := NewCounter()
+
+	steps := []struct {
+		description string
+		fn          func()
+		expected    []Sample
+	}{
+		{
+			description: "first step",
+			fn: func() {
+				c.With("code", "200").Add(2)
+				c.With("code", "500").Add(1)
+			},
+			expected: []Sample{
+				{Labels: []Label{{Name: "code", Value: "200"}}, Value: 2},
+				{Labels: []Label{{Name: "code", Value: "500"}}, Value: 1},
+			},
+		}, {
+			description: "second step only changes one series",
+			fn: func() {
+				c.With("code", "200").Add(3)
+			},
+			expected: []Sample{
+				{Labels: []Label{{Name: "code", Value: "200"}}, Value: 3},
+			},
+		}, {
+			description: "nothing changes",
+			fn:          func() {},
+		},
+	}
+
+	prev := c.Snapshot()
+	for _, step := range steps {
+		step.fn()
+		next := c.Snapshot()
+		assert.Equal(t, step.expected, next.Delta(prev).Samples(), step.description)
+		prev = next
+	}
+
+	total, ok := prev.Get("code", "200")
+	assert.True(t, ok)
+	assert.Equal(t, 5.0, total)
+	assert.Equal(t, KindCounter, prev.Kind())
+	assert.Nil(t, prev.HistogramSamples())
+
+	_, ok = prev.Observations("code", "200")
+	assert.False(t, ok)
+
+	// Snapshots are not affected by later updates or a reset.
+	c.Reset()
+	assert.Nil(t, c.Value())
+	total, _ = prev.Get("code", "200")
+	assert.Equal(t, 5.0, total)
+
+	c.With("code", "200").Add(1)
+	assert.Equal(t, map[string]float64{"200": 1}, c.Value())
+}
+
+func TestGaugeSnapshotDelta(t *testing.T) {
+	assert := assert.New(t)
+
+	g := NewGauge()
+	g.With("queue", "a").Set(10)
+	g.With("queue", "b").Set(5)
+	before := g.Snapshot()
+
+	g.With("queue", "a").Set(4)
+	g.With("queue", "b").Set(5)
+	g.With("queue", "c").Set(0)
+	after := g.Snapshot()
+
+	assert.Equal([]Sample{
+		{Labels: []Label{{Name: "queue", Value: "a"}}, Value: -6},
+		{Labels: []Label{{Name: "queue", Value: "c"}}, Value: 0},
+	}, after.Delta(before).Samples())
+
+	assert.Equal(after.Samples(), after.Delta(nil).Samples())
+	assert.Panics(func() { after.Delta(NewCounter().Snapshot()) })
+}
+
+func TestHistogramSnapshotDelta(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewHistogram()
+	h.With("route", "/x").Observe(1)
+	h.With("route", "/y").Observe(2)
+	before := h.Snapshot()
+
+	h.With("route", "/x").Observe(3)
+	h.With("route", "/x").Observe(4)
+	after := h.Snapshot()
+
+	delta := after.Delta(before)
+	assert.Equal(KindHistogram, delta.Kind())
+	assert.Nil(delta.Samples())
+	assert.Equal([]HistogramSample{
+		{Labels: []Label{{Name: "route", Value: "/x"}}, Values: []float64{3, 4}},
+	}, delta.HistogramSamples())
+
+	got, ok := after.Observations("route", "/x")
+	assert.True(ok)
+	assert.Equal([]float64{1, 3, 4}, got)
+
+	_, ok = after.Get("route", "/x")
+	assert.False(ok)
+
+	h.Reset()
+	assert.Nil(h.Value())
+	assert.Len(after.HistogramSamples(), 2)
+}
+
+func TestSnapshotDeltaAfterReset(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewCounter()
+	c.With("code", "200").Add(5)
+	before := c.Snapshot()
+	c.Reset()
+	c.With("code", "200").Add(1)
+
+	assert.Equal([]Sample{
+		{Labels: []Label{{Name: "code", Value: "200"}}, Value: 1},
+	}, c.Snapshot().Delta(before).Samples())
+
+	g := NewGauge()
+	g.With("queue", "a").Set(5)
+	before = g.Snapshot()
+	g.Reset()
+	g.With("queue", "a").Set(5)
+
+	assert.Equal([]Sample{
+		{Labels: []Label{{Name: "queue", Value: "a"}}, Value: 5},
+	}, g.Snapshot().Delta(before).Samples())
+
+	tests := []struct {
+		description string
+		opt         Option
+		expected    []float64
+	}{
+		{
+			description: "retain all",
+			opt:         RetainAll(),
+			expected:    []float64{2},
+		}, {
+			// Only the count and sum are known when the values aren't retained,
+			// so the series is present but has no observations.
+			description: "streaming",
+			opt:         Streaming(),
+		},
+	}
+
+	for _, tc := range tests {
+		h := NewHistogram(tc.opt)
+		h.With("route", "/x").Observe(1)
+		before = h.Snapshot()
+		h.Reset()
+		h.With("route", "/x").Observe(2)
+
+		delta := h.Snapshot().Delta(before)
+		assert.Len(delta.HistogramSamples(), 1, tc.description)
+
+		obs, ok := delta.Observations("route", "/x")
+		assert.True(ok, tc.description)
+		assert.Equal(tc.expected, obs, tc.description)
+	}
+}
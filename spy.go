@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	kit "github.com/go-kit/kit/metrics"
+)
+
+// SpyCounter creates a new counter that forwards every call to With and Add to
+// the wrapped counter, while recording the same data as NewCounter.  The
+// options apply to the recorded data only; the wrapped counter always receives
+// the calls exactly as they were made, even when they fail validation.
+func SpyCounter(wrapped kit.Counter, opts ...Option) *Counter {
+	c := NewCounter(opts...)
+	c.spy = wrapped
+	return c
+}
+
+// SpyGauge creates a new gauge that forwards every call to With, Set and Add
+// to the wrapped gauge, while recording the same data as NewGauge.  The
+// options apply to the recorded data only; the wrapped gauge always receives
+// the calls exactly as they were made, even when they fail validation.
+func SpyGauge(wrapped kit.Gauge, opts ...Option) *Gauge {
+	g := NewGauge(opts...)
+	g.spy = wrapped
+	return g
+}
+
+// SpyHistogram creates a new histogram that forwards every call to With and
+// Observe to the wrapped histogram, while recording the same data as
+// NewHistogram.  The options apply to the recorded data only; the wrapped
+// histogram always receives the calls exactly as they were made, even when
+// they fail validation.
+func SpyHistogram(wrapped kit.Histogram, opts ...Option) *Histogram {
+	h := NewHistogram(opts...)
+	h.spy = wrapped
+	return h
+}
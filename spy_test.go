@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpyCounter(t *testing.T) {
+	assert := assert.New(t)
+
+	wrapped := NewCounter()
+	spy := SpyCounter(wrapped, ExpectLabels("code", "method"))
+
+	spy.With("code", "200").With("method", "GET").Add(1)
+	spy.With("code", "500", "method", "PUT").Add(2)
+
+	expected := map[string]float64{
+		"200.GET": 1,
+		"500.PUT": 2,
+	}
+	assert.Equal(expected, wrapped.Value())
+	assert.Equal(expected, spy.Value())
+}
+
+func TestSpyGauge(t *testing.T) {
+	assert := assert.New(t)
+
+	wrapped := NewGauge()
+	spy := SpyGauge(wrapped, ExpectLabels("code"))
+
+	spy.With("code", "200").Set(5)
+	spy.With("code", "200").Add(-2)
+
+	expected := map[string]float64{"200": 3}
+	assert.Equal(expected, wrapped.Value())
+	assert.Equal(expected, spy.Value())
+}
+
+func TestSpyHistogram(t *testing.T) {
+	assert := assert.New(t)
+
+	wrapped := NewHistogram()
+	spy := SpyHistogram(wrapped, ExpectLabels("route"))
+
+	spy.With("route", "/x").Observe(1.5)
+	spy.With("route", "/x").Observe(2)
+
+	expected := map[string][]float64{"/x": {1.5, 2}}
+	assert.Equal(expected, wrapped.Value())
+	assert.Equal(expected, spy.Value())
+}
+
+func TestSpyForwardsInvalidCalls(t *testing.T) {
+	assert := assert.New(t)
+
+	tb := &fakeTB{TB: t}
+	wrapped := NewCounter()
+	spy := SpyCounter(wrapped, WithT(tb), ExpectLabels("code"))
+
+	spy.With("method", "GET").With("code", "200").Add(1)
+	spy.With("code", "200").Add(1)
+
+	assert.Len(tb.Errors(), 1)
+	assert.Equal(map[string]float64{"GET.200": 1, "200": 1}, wrapped.Value())
+	assert.Equal(map[string]float64{"200": 1}, spy.Value())
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"math"
+	"sort"
+)
+
+// Interpolation selects how a quantile is computed when it falls between two
+// observations.
+type Interpolation int
+
+const (
+	// InterpolationLinear interpolates linearly between the two observations.
+	// This is the default.
+	InterpolationLinear Interpolation = iota
+
+	// InterpolationLower uses the lower of the two observations.
+	InterpolationLower
+
+	// InterpolationHigher uses the higher of the two observations.
+	InterpolationHigher
+
+	// InterpolationNearest uses the nearest of the two observations.
+	InterpolationNearest
+
+	// InterpolationMidpoint uses the average of the two observations.
+	InterpolationMidpoint
+)
+
+// Stats are the summary statistics of a histogram series.
+type Stats struct {
+	Count  uint64
+	Sum    float64
+	Min    float64
+	Max    float64
+	Mean   float64
+	StdDev float64
+	P50    float64
+	P90    float64
+	P99    float64
+}
+
+// Bucket is a cumulative Prometheus style histogram bucket.
+type Bucket struct {
+	// UpperBound is the inclusive upper bound of the bucket.
+	UpperBound float64
+
+	// Count is the number of observations less than or equal to UpperBound.
+	Count uint64
+}
+
+// Stats returns the summary statistics of the series with exactly the provided
+// label name and value pairs, in any order.  If no series matches, false is
+// returned.
+//
+// The standard deviation is the population standard deviation.  The quantiles
+// use the interpolation set by QuantileInterpolation, and are estimated when
+// Reservoir or Streaming storage is used.
+func (h *Histogram) Stats(labelValues ...string) (Stats, bool) {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	var rv Stats
+	ok := h.find(labelValues, func(o *observations) {
+		rv = o.stats(root.interpolation)
+	})
+
+	return rv, ok
+}
+
+// Buckets returns the cumulative bucket counts of the series with exactly the
+// provided label name and value pairs, in any order.  The last bucket always
+// has an upper bound of +Inf.  If bounds is nil, the bounds set by
+// BucketBounds are used.  If no series matches, false is returned.
+//
+// The counts are exact unless Reservoir or Streaming storage is used, in which
+// case they are estimated from what was retained.  The +Inf bucket is always
+// exact.
+func (h *Histogram) Buckets(bounds []float64, labelValues ...string) ([]Bucket, bool) {
+	if bounds == nil {
+		bounds = h.bucketBounds()
+	} else {
+		bounds = sortBounds(bounds)
+	}
+
+	var counts []uint64
+	var total uint64
+	ok := h.find(labelValues, func(o *observations) {
+		counts = o.buckets(bounds)
+		total = o.count
+	})
+	if !ok {
+		return nil, false
+	}
+
+	rv := make([]Bucket, 0, len(bounds)+1)
+	for i, bound := range bounds {
+		rv = append(rv, Bucket{
+			UpperBound: bound,
+			Count:      counts[i],
+		})
+	}
+	rv = append(rv, Bucket{
+		UpperBound: math.Inf(1),
+		Count:      total,
+	})
+
+	return rv, true
+}
+
+// sortBounds returns a sorted copy of the bucket upper bounds.  Any +Inf bound
+// is removed since the +Inf bucket is always added.  The copy is never nil,
+// even when there are no finite bounds, so it isn't mistaken for the defaults.
+func sortBounds(bounds []float64) []float64 {
+	rv := make([]float64, 0, len(bounds))
+	for _, b := range bounds {
+		if !math.IsInf(b, 1) {
+			rv = append(rv, b)
+		}
+	}
+	sort.Float64s(rv)
+
+	return rv
+}
+
+// quantile returns the q quantile of the sorted values.
+func quantile(sorted []float64, q float64, m Interpolation) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+
+	switch m {
+	case InterpolationLower:
+		return sorted[lo]
+	case InterpolationHigher:
+		return sorted[hi]
+	case InterpolationNearest:
+		return sorted[int(math.Round(pos))]
+	case InterpolationMidpoint:
+		return (sorted[lo] + sorted[hi]) / 2
+	}
+
+	return sorted[lo] + (pos-float64(lo))*(sorted[hi]-sorted[lo])
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramStats(t *testing.T) {
+	tests := []struct {
+		description string
+		opt         Option
+		p50         float64
+		p90         float64
+		p99         float64
+	}{
+		{
+			description: "linear by default",
+			p50:         5.5,
+			p90:         9.1,
+			p99:         9.91,
+		}, {
+			description: "lower",
+			opt:         QuantileInterpolation(InterpolationLower),
+			p50:         5,
+			p90:         9,
+			p99:         9,
+		}, {
+			description: "higher",
+			opt:         QuantileInterpolation(InterpolationHigher),
+			p50:         6,
+			p90:         10,
+			p99:         10,
+		}, {
+			description: "nearest",
+			opt:         QuantileInterpolation(InterpolationNearest),
+			p50:         6,
+			p90:         9,
+			p99:         10,
+		}, {
+			description: "midpoint",
+			opt:         QuantileInterpolation(InterpolationMidpoint),
+			p50:         5.5,
+			p90:         9.5,
+			p99:         9.5,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			h := NewHistogram(tc.opt)
+			for _, v := range []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1} {
+				h.With("route", "/x").Observe(v)
+			}
+
+			got, ok := h.Stats("route", "/x")
+			assert.True(ok)
+			assert.Equal(uint64(10), got.Count)
+			assert.Equal(55.0, got.Sum)
+			assert.Equal(1.0, got.Min)
+			assert.Equal(10.0, got.Max)
+			assert.Equal(5.5, got.Mean)
+			assert.InDelta(math.Sqrt(8.25), got.StdDev, 1e-9)
+			assert.InDelta(tc.p50, got.P50, 1e-9)
+			assert.InDelta(tc.p90, got.P90, 1e-9)
+			assert.InDelta(tc.p99, got.P99, 1e-9)
+
+			_, ok = h.Stats("route", "/y")
+			assert.False(ok)
+		})
+	}
+}
+
+func TestHistogramStatsSingleObservation(t *testing.T) {
+	h := NewHistogram()
+	h.Observe(2)
+
+	got, ok := h.Stats()
+	assert.True(t, ok)
+	assert.Equal(t, Stats{
+		Count: 1,
+		Sum:   2,
+		Min:   2,
+		Max:   2,
+		Mean:  2,
+		P50:   2,
+		P90:   2,
+		P99:   2,
+	}, got)
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewHistogram(BucketBounds(0.1, 1))
+	for _, v := range []float64{0.05, 0.1, 0.5, 1, 5} {
+		h.Observe(v)
+	}
+
+	got, ok := h.Buckets(nil)
+	assert.True(ok)
+	assert.Equal([]Bucket{
+		{UpperBound: 0.1, Count: 2},
+		{UpperBound: 1, Count: 4},
+		{UpperBound: math.Inf(1), Count: 5},
+	}, got)
+
+	got, ok = h.Buckets([]float64{10, 0.5})
+	assert.True(ok)
+	assert.Equal([]Bucket{
+		{UpperBound: 0.5, Count: 3},
+		{UpperBound: 10, Count: 5},
+		{UpperBound: math.Inf(1), Count: 5},
+	}, got)
+
+	_, ok = h.Buckets(nil, "route", "/x")
+	assert.False(ok)
+
+	// An explicit +Inf bound doesn't add a second +Inf bucket.
+	got, ok = h.Buckets([]float64{math.Inf(1), 1})
+	assert.True(ok)
+	assert.Equal([]Bucket{
+		{UpperBound: 1, Count: 4},
+		{UpperBound: math.Inf(1), Count: 5},
+	}, got)
+
+	h = NewHistogram(BucketBounds(1, math.Inf(1)))
+	h.Observe(0.5)
+	got, ok = h.Buckets(nil)
+	assert.True(ok)
+	assert.Equal([]Bucket{
+		{UpperBound: 1, Count: 1},
+		{UpperBound: math.Inf(1), Count: 1},
+	}, got)
+
+	var b strings.Builder
+	assert.NoError(WriteExposition(&b, "size", h))
+	assert.Equal(1, strings.Count(b.String(), `le="+Inf"`))
+
+	// No finite bounds leaves only the +Inf bucket, rather than the defaults.
+	for _, h := range []*Histogram{NewHistogram(BucketBounds()), NewHistogram(BucketBounds(math.Inf(1)))} {
+		h.Observe(0.5)
+		got, ok = h.Buckets(nil)
+		assert.True(ok)
+		assert.Equal([]Bucket{
+			{UpperBound: math.Inf(1), Count: 1},
+		}, got)
+	}
+}
@@ -0,0 +1,315 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// storageMode is how a histogram stores its observations.
+type storageMode int
+
+const (
+	storeAll storageMode = iota
+	storeReservoir
+	storeStreaming
+)
+
+// sketchAccuracy is the relative accuracy of the quantiles reported when
+// using Streaming storage.
+const sketchAccuracy = 0.01
+
+// storage is the storage strategy of a tree of histograms.
+type storage struct {
+	mode storageMode
+	size int
+	rand *rand.Rand
+}
+
+// observations are the observations of a single histogram series.  The count,
+// sum, min, max, mean and variance are always exact, regardless of what is
+// retained by the storage strategy.
+type observations struct {
+	storage *storage
+	count   uint64
+	sum     float64
+	min     float64
+	max     float64
+	mean    float64
+	m2      float64
+	values  []float64
+	sketch  *sketch
+}
+
+func newObservations(s *storage) *observations {
+	o := observations{
+		storage: s,
+		min:     math.Inf(1),
+		max:     math.Inf(-1),
+	}
+	if s.mode == storeStreaming {
+		o.sketch = newSketch(sketchAccuracy)
+	}
+	return &o
+}
+
+// observe records the value.  The series lock must be held by the caller.
+func (o *observations) observe(v float64) {
+	o.count++
+	o.sum += v
+	o.min = math.Min(o.min, v)
+	o.max = math.Max(o.max, v)
+
+	// Welford's algorithm keeps the variance accurate without the values.
+	d := v - o.mean
+	o.mean += d / float64(o.count)
+	o.m2 += d * (v - o.mean)
+
+	switch o.storage.mode {
+	case storeAll:
+		o.values = append(o.values, v)
+	case storeReservoir:
+		if len(o.values) < o.storage.size {
+			o.values = append(o.values, v)
+			break
+		}
+		if i := o.storage.rand.Int63n(int64(o.count)); i < int64(o.storage.size) {
+			o.values[i] = v
+		}
+	case storeStreaming:
+		o.sketch.add(v)
+	}
+}
+
+// clone returns a deep copy of the observations.
+func (o *observations) clone() *observations {
+	c := *o
+	c.values = append([]float64(nil), o.values...)
+	if o.sketch != nil {
+		c.sketch = o.sketch.clone()
+	}
+	return &c
+}
+
+// since returns the observations made after the older observations were
+// taken.  Only the count and sum are known unless every value is retained.
+func (o *observations) since(older *observations) *observations {
+	if o.storage.mode == storeAll {
+		values := o.values
+		if older != nil && len(older.values) <= len(values) {
+			values = values[len(older.values):]
+		}
+
+		rv := newObservations(o.storage)
+		for _, v := range values {
+			rv.observe(v)
+		}
+		return rv
+	}
+
+	rv := &observations{
+		storage: o.storage,
+		count:   o.count,
+		sum:     o.sum,
+		min:     math.NaN(),
+		max:     math.NaN(),
+		mean:    math.NaN(),
+		m2:      math.NaN(),
+	}
+	if older != nil && older.count <= o.count {
+		rv.count -= older.count
+		rv.sum -= older.sum
+	}
+	return rv
+}
+
+// stats returns the summary statistics.  The quantiles are exact when every
+// value is retained, estimated from the sample with Reservoir storage and
+// estimated from the sketch with Streaming storage.
+func (o *observations) stats(m Interpolation) Stats {
+	if o.count == 0 {
+		return Stats{}
+	}
+
+	rv := Stats{
+		Count:  o.count,
+		Sum:    o.sum,
+		Min:    o.min,
+		Max:    o.max,
+		Mean:   o.sum / float64(o.count),
+		StdDev: math.Sqrt(o.m2 / float64(o.count)),
+	}
+
+	if o.sketch != nil {
+		rv.P50 = o.sketch.quantile(0.5)
+		rv.P90 = o.sketch.quantile(0.9)
+		rv.P99 = o.sketch.quantile(0.99)
+		return rv
+	}
+
+	sorted := append([]float64(nil), o.values...)
+	sort.Float64s(sorted)
+
+	rv.P50 = quantile(sorted, 0.5, m)
+	rv.P90 = quantile(sorted, 0.9, m)
+	rv.P99 = quantile(sorted, 0.99, m)
+
+	return rv
+}
+
+// buckets returns the cumulative number of observations less than or equal to
+// each of the sorted bounds.  The counts are exact when every value is
+// retained, and estimated otherwise.
+func (o *observations) buckets(bounds []float64) []uint64 {
+	if o.sketch != nil {
+		counts := make([]uint64, len(bounds))
+		for i, bound := range bounds {
+			counts[i] = o.sketch.countAtOrBelow(bound)
+		}
+		return counts
+	}
+
+	counts := cumulativeBuckets(o.values, bounds)
+	if n := uint64(len(o.values)); n != 0 && n != o.count {
+		for i := range counts {
+			counts[i] = uint64(math.Round(float64(counts[i]) * float64(o.count) / float64(n)))
+		}
+	}
+	return counts
+}
+
+// sketch is a simple quantile sketch with relative accuracy guarantees, based
+// on DDSketch.  Values are placed in logarithmically sized buckets so any
+// quantile is within the relative accuracy of the true value.  Infinities and
+// NaN have no logarithmic bucket, so they are counted on their own and are
+// ordered the way sort.Float64s orders them.
+type sketch struct {
+	gamma    float64
+	positive map[int]uint64
+	negative map[int]uint64
+	zero     uint64
+	posInf   uint64
+	negInf   uint64
+	nan      uint64
+	count    uint64
+}
+
+func newSketch(accuracy float64) *sketch {
+	return &sketch{
+		gamma:    (1 + accuracy) / (1 - accuracy),
+		positive: map[int]uint64{},
+		negative: map[int]uint64{},
+	}
+}
+
+func (s *sketch) index(v float64) int {
+	return int(math.Ceil(math.Log(v) / math.Log(s.gamma)))
+}
+
+// estimate returns the representative value of the bucket at the index.
+func (s *sketch) estimate(i int) float64 {
+	return 2 * math.Pow(s.gamma, float64(i)) / (s.gamma + 1)
+}
+
+func (s *sketch) add(v float64) {
+	s.count++
+	switch {
+	case math.IsNaN(v):
+		s.nan++
+	case math.IsInf(v, 1):
+		s.posInf++
+	case math.IsInf(v, -1):
+		s.negInf++
+	case v > 0:
+		s.positive[s.index(v)]++
+	case v < 0:
+		s.negative[s.index(-v)]++
+	default:
+		s.zero++
+	}
+}
+
+func (s *sketch) clone() *sketch {
+	c := *s
+	c.positive = make(map[int]uint64, len(s.positive))
+	for k, v := range s.positive {
+		c.positive[k] = v
+	}
+	c.negative = make(map[int]uint64, len(s.negative))
+	for k, v := range s.negative {
+		c.negative[k] = v
+	}
+	return &c
+}
+
+type sketchBucket struct {
+	value float64
+	count uint64
+}
+
+// sorted returns the buckets ordered by their representative values, with NaN
+// first.
+func (s *sketch) sorted() []sketchBucket {
+	rv := make([]sketchBucket, 0, len(s.negative)+len(s.positive)+4)
+	if s.nan > 0 {
+		rv = append(rv, sketchBucket{value: math.NaN(), count: s.nan})
+	}
+	if s.negInf > 0 {
+		rv = append(rv, sketchBucket{value: math.Inf(-1), count: s.negInf})
+	}
+
+	finite := len(rv)
+	for i, n := range s.negative {
+		rv = append(rv, sketchBucket{value: -s.estimate(i), count: n})
+	}
+	if s.zero > 0 {
+		rv = append(rv, sketchBucket{count: s.zero})
+	}
+	for i, n := range s.positive {
+		rv = append(rv, sketchBucket{value: s.estimate(i), count: n})
+	}
+
+	sort.Slice(rv[finite:], func(i, j int) bool {
+		return rv[finite+i].value < rv[finite+j].value
+	})
+
+	if s.posInf > 0 {
+		rv = append(rv, sketchBucket{value: math.Inf(1), count: s.posInf})
+	}
+	return rv
+}
+
+func (s *sketch) quantile(q float64) float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+
+	rank := q * float64(s.count-1)
+	seen := uint64(0)
+	buckets := s.sorted()
+	for _, b := range buckets {
+		seen += b.count
+		if float64(seen) > rank {
+			return b.value
+		}
+	}
+	return buckets[len(buckets)-1].value
+}
+
+func (s *sketch) countAtOrBelow(bound float64) uint64 {
+	rv := uint64(0)
+	for _, b := range s.sorted() {
+		// Like cumulativeBuckets, NaN is only counted in the +Inf bucket.
+		if math.IsNaN(b.value) {
+			continue
+		}
+		if b.value > bound {
+			break
+		}
+		rv += b.count
+	}
+	return rv
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramStorage(t *testing.T) {
+	tests := []struct {
+		description string
+		opt         Option
+		retained    int
+		delta       float64 // the allowed relative error of the estimates
+	}{
+		{
+			description: "retain all",
+			opt:         RetainAll(),
+			retained:    10000,
+		}, {
+			description: "reservoir",
+			opt:         Reservoir(500),
+			retained:    500,
+			delta:       0.1,
+		}, {
+			description: "streaming",
+			opt:         Streaming(),
+			delta:       0.01,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			h := NewHistogram(tc.opt)
+			h.Expect().ObservedBetween(1, 10000)
+			h.Expect().ObservedTimes(10000)
+
+			for i := 1; i <= 10000; i++ {
+				h.Observe(float64(i))
+			}
+
+			assert.NoError(h.Verify())
+			assert.Len(h.Value()[""], tc.retained)
+
+			stats, ok := h.Stats()
+			require.True(ok)
+			assert.Equal(uint64(10000), stats.Count)
+			assert.Equal(50005000.0, stats.Sum)
+			assert.Equal(1.0, stats.Min)
+			assert.Equal(10000.0, stats.Max)
+			assert.Equal(5000.5, stats.Mean)
+			assert.InDelta(2886.75, stats.StdDev, 0.01)
+			assert.InEpsilon(5000.5, stats.P50, tc.delta+1e-9)
+			assert.InEpsilon(9000.1, stats.P90, tc.delta+1e-9)
+			assert.InEpsilon(9900.01, stats.P99, tc.delta+1e-9)
+
+			buckets, ok := h.Buckets([]float64{1000, 5000})
+			require.True(ok)
+			require.Len(buckets, 3)
+			assert.InEpsilon(1000, float64(buckets[0].Count), 3*tc.delta+1e-9)
+			assert.InEpsilon(5000, float64(buckets[1].Count), 3*tc.delta+1e-9)
+			assert.Equal(uint64(10000), buckets[2].Count)
+
+			var b strings.Builder
+			require.NoError(WriteExposition(&b, "h", h))
+			assert.Contains(b.String(), "h_sum 5.0005e+07\nh_count 10000\n")
+
+			before := h.Snapshot()
+			h.Observe(1)
+			h.Observe(2)
+			assert.Empty(before.Delta(before).HistogramSamples())
+
+			var out strings.Builder
+			require.NoError(writeHistogram(&out, "h", nil, h.Snapshot().Delta(before)))
+			assert.Equal("# TYPE h histogram\nh_bucket{le=\"+Inf\"} 2\nh_sum 3\nh_count 2\n", out.String())
+		})
+	}
+}
+
+func TestSketch(t *testing.T) {
+	assert := assert.New(t)
+
+	s := newSketch(sketchAccuracy)
+	assert.True(math.IsNaN(s.quantile(0.5)))
+
+	for _, v := range []float64{-10, -5, 0, 5, 10} {
+		s.add(v)
+	}
+
+	assert.Equal(0.0, s.quantile(0.5))
+	assert.InEpsilon(-10, s.quantile(0), sketchAccuracy)
+	assert.InEpsilon(10, s.quantile(1), sketchAccuracy)
+	assert.InEpsilon(5, s.quantile(0.75), sketchAccuracy)
+	assert.Equal(uint64(2), s.countAtOrBelow(-1))
+	assert.Equal(uint64(3), s.countAtOrBelow(0))
+	assert.Equal(uint64(5), s.countAtOrBelow(100))
+
+	c := s.clone()
+	c.add(100)
+	assert.Equal(uint64(5), s.count)
+	assert.Equal(uint64(6), c.count)
+}
+
+func TestSketchNonFinite(t *testing.T) {
+	assert := assert.New(t)
+
+	s := newSketch(sketchAccuracy)
+	for _, v := range []float64{5, math.Inf(1), math.Inf(-1), math.NaN(), 10} {
+		s.add(v)
+	}
+
+	assert.True(math.IsNaN(s.quantile(0)))
+	assert.True(math.IsInf(s.quantile(0.25), -1))
+	assert.InEpsilon(5, s.quantile(0.5), sketchAccuracy)
+	assert.InEpsilon(10, s.quantile(0.75), sketchAccuracy)
+	assert.True(math.IsInf(s.quantile(1), 1))
+	assert.Equal(uint64(1), s.countAtOrBelow(0))
+	assert.Equal(uint64(3), s.countAtOrBelow(100))
+	assert.Equal(uint64(4), s.countAtOrBelow(math.Inf(1)))
+}
+
+func TestStreamingInfinity(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	h := NewHistogram(Streaming())
+	h.Observe(1)
+	h.Observe(math.Inf(1))
+	h.Observe(math.Inf(1))
+
+	stats, ok := h.Stats()
+	require.True(ok)
+	assert.Equal(1.0, stats.Min)
+	assert.True(math.IsInf(stats.P50, 1))
+	assert.True(math.IsInf(stats.P90, 1))
+	assert.True(math.IsInf(stats.P99, 1))
+
+	buckets, ok := h.Buckets([]float64{10})
+	require.True(ok)
+	assert.Equal([]Bucket{
+		{UpperBound: 10, Count: 1},
+		{UpperBound: math.Inf(1), Count: 3},
+	}, buckets)
+}
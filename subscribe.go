@@ -0,0 +1,267 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Update describes a single change to a metric.
+type Update struct {
+	// Kind is the kind of metric that was updated.
+	Kind Kind
+
+	// Op is the operation that was performed.
+	Op Op
+
+	// Labels are the label name and value pairs of the series.
+	Labels []Label
+
+	// Arg is the value passed to Add, Set or Observe.
+	Arg float64
+
+	// Value is the value of the series after the update.  For histograms this
+	// is the number of observations of the series.
+	Value float64
+}
+
+// listeners are the hooks and subscriptions of a tree of metrics.  They are
+// protected by the root lock, but are always called without it held.
+type listeners struct {
+	hooks      []func(Update)
+	subs       []*subscription
+	subscribed atomic.Bool
+}
+
+// empty returns true if there is nothing to deliver updates to.  It is safe to
+// call without the root lock held.
+func (l *listeners) empty() bool {
+	return len(l.hooks) == 0 && !l.subscribed.Load()
+}
+
+// prepare delivers the update to the subscriptions and returns a function
+// that calls the hooks.  It must be called with the root lock held, so the
+// subscriptions receive the updates in the order they were made; sending never
+// blocks.  The function it returns must be called after the lock is released.
+func (l *listeners) prepare(kind Kind, op Op, lvp []tuple, arg, value float64) func() {
+	if len(l.hooks) == 0 && len(l.subs) == 0 {
+		return func() {}
+	}
+
+	u := Update{
+		Kind:   kind,
+		Op:     op,
+		Labels: toLabels(lvp),
+		Arg:    arg,
+		Value:  value,
+	}
+
+	for _, s := range l.subs {
+		s.send(u)
+	}
+
+	// The slice is never modified in place, so a copy of it is safe to use
+	// once the lock is released.
+	hooks := l.hooks
+	return func() {
+		for _, hook := range hooks {
+			hook(u)
+		}
+	}
+}
+
+func (l *listeners) subscribe() <-chan Update {
+	s := newSubscription()
+	l.subs = append(l.subs[:len(l.subs):len(l.subs)], s)
+	l.subscribed.Store(true)
+	return s.ch
+}
+
+func (l *listeners) unsubscribe(ch <-chan Update) {
+	subs := make([]*subscription, 0, len(l.subs))
+	for _, s := range l.subs {
+		if s.ch == ch {
+			s.close()
+			continue
+		}
+		subs = append(subs, s)
+	}
+	l.subs = subs
+	l.subscribed.Store(len(subs) > 0)
+}
+
+func (l *listeners) unsubscribeAll() {
+	for _, s := range l.subs {
+		s.close()
+	}
+	l.subs = nil
+	l.subscribed.Store(false)
+}
+
+// subscription delivers updates to a channel in order, without ever blocking
+// the code updating the metric.
+type subscription struct {
+	ch    chan Update
+	m     sync.Mutex
+	queue []Update
+	wake  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newSubscription() *subscription {
+	s := subscription{
+		ch:   make(chan Update),
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go s.run()
+
+	return &s
+}
+
+func (s *subscription) send(u Update) {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	s.m.Lock()
+	s.queue = append(s.queue, u)
+	s.m.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscription) close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *subscription) run() {
+	defer close(s.ch)
+
+	for {
+		s.m.Lock()
+		if len(s.queue) == 0 {
+			s.m.Unlock()
+
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+
+		u := s.queue[0]
+		s.queue = s.queue[1:]
+		s.m.Unlock()
+
+		select {
+		case s.ch <- u:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Subscribe returns a channel that receives every update made to the tree of
+// counters after Subscribe returns, in order.  Updates are queued so the code
+// updating the counter is never blocked.  The channel is closed by
+// Unsubscribe, or when the test completes if WithT was provided; updates that
+// are still queued at that point are dropped.
+func (c *Counter) Subscribe() <-chan Update {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return root.listeners.subscribe()
+}
+
+// Unsubscribe stops the delivery of updates to the channel, and closes it.
+// Updates that are still queued are dropped.
+func (c *Counter) Unsubscribe(ch <-chan Update) {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	root.listeners.unsubscribe(ch)
+}
+
+// Subscribe returns a channel that receives every update made to the gauge
+// after Subscribe returns, in order.  Updates are queued so the code updating
+// the gauge is never blocked.  The channel is closed by Unsubscribe, or when
+// the test completes if WithT was provided; updates that are still queued at
+// that point are dropped.
+func (g *Gauge) Subscribe() <-chan Update {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return root.listeners.subscribe()
+}
+
+// Unsubscribe stops the delivery of updates to the channel, and closes it.
+// Updates that are still queued are dropped.
+func (g *Gauge) Unsubscribe(ch <-chan Update) {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	root.listeners.unsubscribe(ch)
+}
+
+// Subscribe returns a channel that receives every update made to the
+// histogram after Subscribe returns, in order.  Updates are queued so the code
+// updating the histogram is never blocked.  The channel is closed by
+// Unsubscribe, or when the test completes if WithT was provided; updates that
+// are still queued at that point are dropped.
+func (h *Histogram) Subscribe() <-chan Update {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	return root.listeners.subscribe()
+}
+
+// Unsubscribe stops the delivery of updates to the channel, and closes it.
+// Updates that are still queued are dropped.
+func (h *Histogram) Unsubscribe(ch <-chan Update) {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	root.m.Lock()
+	defer root.m.Unlock()
+
+	root.listeners.unsubscribe(ch)
+}
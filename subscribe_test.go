@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	var m sync.Mutex
+	var updates []Update
+	hook := OnUpdate(func(u Update) {
+		m.Lock()
+		defer m.Unlock()
+		updates = append(updates, u)
+	})
+
+	c := NewCounter(hook)
+	g := NewGauge(hook)
+	h := NewHistogram(hook)
+
+	// The hook can query the metric since the lock isn't held.
+	ctx, cancel := context.WithCancel(context.Background())
+	errors := NewCounter(OnUpdate(func(u Update) {
+		cancel()
+	}))
+
+	c.With("code", "200").Add(2)
+	c.With("code", "200").Add(3)
+	g.Set(10)
+	g.Add(-4)
+	h.With("route", "/x").Observe(0.5)
+	h.With("route", "/x").Observe(1.5)
+	errors.Add(1)
+
+	<-ctx.Done()
+
+	assert.Equal([]Update{
+		{Kind: KindCounter, Op: OpAdd, Labels: []Label{{Name: "code", Value: "200"}}, Arg: 2, Value: 2},
+		{Kind: KindCounter, Op: OpAdd, Labels: []Label{{Name: "code", Value: "200"}}, Arg: 3, Value: 5},
+		{Kind: KindGauge, Op: OpSet, Labels: []Label{}, Arg: 10, Value: 10},
+		{Kind: KindGauge, Op: OpAdd, Labels: []Label{}, Arg: -4, Value: 6},
+		{Kind: KindHistogram, Op: OpObserve, Labels: []Label{{Name: "route", Value: "/x"}}, Arg: 0.5, Value: 1},
+		{Kind: KindHistogram, Op: OpObserve, Labels: []Label{{Name: "route", Value: "/x"}}, Arg: 1.5, Value: 2},
+	}, updates)
+}
+
+func TestOnUpdateCanQuery(t *testing.T) {
+	var c *Counter
+	var seen []float64
+	c = NewCounter(OnUpdate(func(Update) {
+		v, _ := c.Get()
+		seen = append(seen, v)
+	}))
+
+	c.Add(1)
+	c.Add(1)
+
+	assert.Equal(t, []float64{1, 2}, seen)
+}
+
+func TestSubscribe(t *testing.T) {
+	tests := []struct {
+		description string
+		fn          func(opts ...Option) (<-chan Update, func(), func(<-chan Update))
+		kind        Kind
+	}{
+		{
+			description: "counter",
+			fn: func(opts ...Option) (<-chan Update, func(), func(<-chan Update)) {
+				c := NewCounter(opts...)
+				return c.Subscribe(), func() { c.Add(1) }, c.Unsubscribe
+			},
+			kind: KindCounter,
+		}, {
+			description: "gauge",
+			fn: func(opts ...Option) (<-chan Update, func(), func(<-chan Update)) {
+				g := NewGauge(opts...)
+				return g.Subscribe(), func() { g.Add(1) }, g.Unsubscribe
+			},
+			kind: KindGauge,
+		}, {
+			description: "histogram",
+			fn: func(opts ...Option) (<-chan Update, func(), func(<-chan Update)) {
+				h := NewHistogram(opts...)
+				return h.Subscribe(), func() { h.Observe(1) }, h.Unsubscribe
+			},
+			kind: KindHistogram,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			ch, update, unsubscribe := tc.fn()
+
+			// Nothing is read until all the updates are made, so the updates
+			// must be queued rather than block.
+			for i := 0; i < 100; i++ {
+				update()
+			}
+			for i := 1; i <= 100; i++ {
+				u, ok := <-ch
+				require.True(ok)
+				assert.Equal(tc.kind, u.Kind)
+				assert.Equal(float64(i), u.Value)
+			}
+
+			unsubscribe(ch)
+			update()
+			_, ok := <-ch
+			assert.False(ok)
+
+			// The subscriptions are closed when the test completes.
+			tb := &fakeTB{TB: t}
+			ch, update, _ = tc.fn(WithT(tb))
+			update()
+			tb.finish()
+			for range ch {
+			}
+			assert.Empty(tb.Errors())
+		})
+	}
+}
+
+func TestSubscribeOrderUnderConcurrency(t *testing.T) {
+	const (
+		goroutines = 8
+		updates    = 200
+	)
+
+	c := NewCounter()
+	ch := c.Subscribe()
+	defer c.Unsubscribe(ch)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < updates; j++ {
+				c.With("code", "200").Add(1)
+			}
+		}()
+	}
+
+	// Each update carries the resulting value, so in order they count up.
+	for want := 1.0; want <= goroutines*updates; want++ {
+		u := <-ch
+		if !assert.Equal(t, want, u.Value) {
+			break
+		}
+	}
+	wg.Wait()
+}
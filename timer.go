@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"sync"
+	"time"
+
+	kit "github.com/go-kit/kit/metrics"
+)
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// FakeClock is a Clock that only moves when told to.  It is safe to use from
+// multiple goroutines.  Pass fc.Now to TimeFunc to have events use the same
+// time.
+type FakeClock struct {
+	m   sync.Mutex
+	now time.Time
+}
+
+var _ Clock = (*FakeClock)(nil)
+
+// NewFakeClock creates a new fake clock set to the provided time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by the duration.  A negative duration moves
+// the clock backward.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Set sets the clock to the provided time.
+func (c *FakeClock) Set(now time.Time) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.now = now
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Timer is a drop-in replacement for the go-kit metrics.Timer that reads the
+// time from a Clock, so the durations it observes can be controlled by a test.
+type Timer struct {
+	h     kit.Histogram
+	clock Clock
+	t     time.Time
+	u     time.Duration
+}
+
+// NewTimer creates a new timer that observes into the histogram, starting now
+// according to the clock.  If the clock is nil, the real time is used.
+func NewTimer(h kit.Histogram, clock Clock) *Timer {
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	return &Timer{
+		h:     h,
+		clock: clock,
+		t:     clock.Now(),
+		u:     time.Second,
+	}
+}
+
+// ObserveDuration observes the time since the timer was created, in the unit
+// of the timer.  A negative duration is observed as zero.
+func (t *Timer) ObserveDuration() {
+	d := float64(t.clock.Now().Sub(t.t).Nanoseconds()) / float64(t.u)
+	if d < 0 {
+		d = 0
+	}
+	t.h.Observe(d)
+}
+
+// Unit sets the unit of the observations.  The default is seconds.
+func (t *Timer) Unit(u time.Duration) {
+	t.u = u
+}
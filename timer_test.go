@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	fc := NewFakeClock(start)
+	assert.Equal(start, fc.Now())
+
+	fc.Advance(1500 * time.Millisecond)
+	assert.Equal(start.Add(1500*time.Millisecond), fc.Now())
+
+	fc.Advance(-time.Second)
+	assert.Equal(start.Add(500*time.Millisecond), fc.Now())
+
+	fc.Set(start)
+	assert.Equal(start, fc.Now())
+}
+
+func TestTimer(t *testing.T) {
+	tests := []struct {
+		description string
+		unit        time.Duration
+		advance     time.Duration
+		expected    float64
+	}{
+		{
+			description: "seconds by default",
+			advance:     1500 * time.Millisecond,
+			expected:    1.5,
+		}, {
+			description: "milliseconds",
+			unit:        time.Millisecond,
+			advance:     1500 * time.Millisecond,
+			expected:    1500,
+		}, {
+			description: "no time passed",
+			expected:    0,
+		}, {
+			description: "the clock moved backward",
+			advance:     -time.Second,
+			expected:    0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			assert := assert.New(t)
+
+			fc := NewFakeClock(time.Unix(1000, 0))
+			h := NewHistogram(ExpectLabels("route"))
+
+			timer := NewTimer(h.With("route", "/x"), fc)
+			if tc.unit != 0 {
+				timer.Unit(tc.unit)
+			}
+			fc.Advance(tc.advance)
+			timer.ObserveDuration()
+
+			assert.Equal(map[string][]float64{"/x": {tc.expected}}, h.Value())
+		})
+	}
+}
+
+func TestTimerRealClock(t *testing.T) {
+	h := NewHistogram()
+
+	NewTimer(h, nil).ObserveDuration()
+
+	stats, ok := h.Stats()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), stats.Count)
+	assert.GreaterOrEqual(t, stats.Min, 0.0)
+}
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// notifier wakes up anything waiting for a tree of metrics to change.  It is
+// safe to use without the root lock held.
+type notifier struct {
+	ch atomic.Pointer[chan struct{}]
+}
+
+// wait returns a channel that is closed on the next change.
+func (n *notifier) wait() <-chan struct{} {
+	for {
+		if ch := n.ch.Load(); ch != nil {
+			return *ch
+		}
+
+		ch := make(chan struct{})
+		if n.ch.CompareAndSwap(nil, &ch) {
+			return ch
+		}
+	}
+}
+
+// notify wakes up everything waiting for a change.
+func (n *notifier) notify() {
+	// Most updates have nothing waiting, so avoid the write when possible.
+	if n.ch.Load() == nil {
+		return
+	}
+
+	if ch := n.ch.Swap(nil); ch != nil {
+		close(*ch)
+	}
+}
+
+// waitFor calls check until it returns true or an error, waiting for the metric
+// to change between calls, or until the context ends.
+func waitFor(ctx context.Context, n *notifier, check func() (bool, error)) error {
+	for {
+		// Get the channel before checking so a change in between isn't missed.
+		ch := n.wait()
+
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// WaitFor blocks until pred returns true for the value of the series with
+// exactly the provided label name and value pairs, in any order, or until the
+// context ends.  pred is only called once the series exists, and again each
+// time the counter changes.  If the labels match more than one series, an
+// error is returned.
+func (c *Counter) WaitFor(ctx context.Context, labelValues []string, pred func(float64) bool) error {
+	root := c.root
+	if root == nil {
+		root = c
+	}
+
+	want, err := convert(labelValues)
+	if err != nil {
+		return err
+	}
+
+	err = waitFor(ctx, &root.changed, func() (bool, error) {
+		s, err := c.lookup(labelValues)
+		if s == nil {
+			return false, err
+		}
+
+		v, _ := s.get()
+		return pred(v), nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for counter %s: %w", formatLabels(want), err)
+	}
+
+	return nil
+}
+
+// WaitUntilAtLeast blocks until the series with exactly the provided label name
+// and value pairs, in any order, has a value of at least n, or until the
+// context ends.
+func (c *Counter) WaitUntilAtLeast(ctx context.Context, n float64, labelValues ...string) error {
+	return c.WaitFor(ctx, labelValues, func(v float64) bool {
+		return v >= n
+	})
+}
+
+// WaitFor blocks until pred returns true for the value of the series with
+// exactly the provided label name and value pairs, in any order, or until the
+// context ends.  pred is only called once the series exists, and again each
+// time the gauge changes.  If the labels match more than one series, an error
+// is returned.
+func (g *Gauge) WaitFor(ctx context.Context, labelValues []string, pred func(float64) bool) error {
+	root := g.root
+	if root == nil {
+		root = g
+	}
+
+	want, err := convert(labelValues)
+	if err != nil {
+		return err
+	}
+
+	err = waitFor(ctx, &root.changed, func() (bool, error) {
+		s, err := g.lookup(labelValues)
+		if s == nil {
+			return false, err
+		}
+
+		return pred(s.get()), nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for gauge %s: %w", formatLabels(want), err)
+	}
+
+	return nil
+}
+
+// WaitUntilAtLeast blocks until the series with exactly the provided label name
+// and value pairs, in any order, has a value of at least n, or until the
+// context ends.
+func (g *Gauge) WaitUntilAtLeast(ctx context.Context, n float64, labelValues ...string) error {
+	return g.WaitFor(ctx, labelValues, func(v float64) bool {
+		return v >= n
+	})
+}
+
+// WaitFor blocks until pred returns true for the statistics of the series with
+// exactly the provided label name and value pairs, in any order, or until the
+// context ends.  pred is only called once the series exists, and again each
+// time the histogram changes.  If the labels match more than one series, an
+// error is returned.
+func (h *Histogram) WaitFor(ctx context.Context, labelValues []string, pred func(Stats) bool) error {
+	root := h.root
+	if root == nil {
+		root = h
+	}
+
+	want, err := convert(labelValues)
+	if err != nil {
+		return err
+	}
+
+	err = waitFor(ctx, &root.changed, func() (bool, error) {
+		s, err := h.lookup(labelValues)
+		if s == nil {
+			return false, err
+		}
+
+		var stats Stats
+		s.read(func(o *observations) {
+			stats = o.stats(root.interpolation)
+		})
+		return pred(stats), nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for histogram %s: %w", formatLabels(want), err)
+	}
+
+	return nil
+}
+
+// WaitUntilAtLeast blocks until the series with exactly the provided label name
+// and value pairs, in any order, has at least n observations, or until the
+// context ends.
+func (h *Histogram) WaitUntilAtLeast(ctx context.Context, n int, labelValues ...string) error {
+	return h.WaitFor(ctx, labelValues, func(s Stats) bool {
+		return s.Count >= uint64(n)
+	})
+}
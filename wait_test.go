@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2023 Comcast Cable Communications Management, LLC
+// SPDX-License-Identifier: Apache-2.0
+
+package mockitmetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	tests := []struct {
+		description string
+		wait        func(context.Context, *Counter, *Gauge, *Histogram) error
+	}{
+		{
+			description: "counter",
+			wait: func(ctx context.Context, c *Counter, _ *Gauge, _ *Histogram) error {
+				return c.WaitUntilAtLeast(ctx, 3, "code", "200")
+			},
+		}, {
+			description: "gauge",
+			wait: func(ctx context.Context, _ *Counter, g *Gauge, _ *Histogram) error {
+				return g.WaitFor(ctx, []string{"queue", "a"}, func(v float64) bool {
+					return v == 3
+				})
+			},
+		}, {
+			description: "gauge at least",
+			wait: func(ctx context.Context, _ *Counter, g *Gauge, _ *Histogram) error {
+				return g.WaitUntilAtLeast(ctx, 3, "queue", "a")
+			},
+		}, {
+			description: "histogram",
+			wait: func(ctx context.Context, _ *Counter, _ *Gauge, h *Histogram) error {
+				return h.WaitUntilAtLeast(ctx, 3, "route", "/x")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := NewCounter()
+			g := NewGauge()
+			h := NewHistogram()
+
+			go func() {
+				for i := 0; i < 3; i++ {
+					c.With("code", "200").Add(1)
+					g.With("queue", "a").Add(1)
+					h.With("route", "/x").Observe(1)
+				}
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			assert.NoError(t, tc.wait(ctx, c, g, h))
+		})
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	c := NewCounter()
+	c.With("code", "200").Add(1)
+	err := c.WaitUntilAtLeast(ctx, 2, "code", "200")
+	assert.ErrorIs(err, context.DeadlineExceeded)
+	assert.ErrorContains(err, `counter {code="200"}`)
+
+	err = NewGauge().WaitUntilAtLeast(ctx, 1)
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	err = NewHistogram().WaitUntilAtLeast(ctx, 1, "route", "/x")
+	assert.ErrorIs(err, context.DeadlineExceeded)
+
+	// Invalid labels fail right away.
+	assert.Error(c.WaitUntilAtLeast(context.Background(), 1, "code"))
+	assert.Error(NewGauge().WaitUntilAtLeast(context.Background(), 1, "queue"))
+	assert.Error(NewHistogram().WaitUntilAtLeast(context.Background(), 1, "route"))
+}
+
+func TestWaitReset(t *testing.T) {
+	c := NewCounter()
+	c.Add(5)
+
+	done := make(chan error)
+	go func() {
+		done <- c.WaitFor(context.Background(), nil, func(v float64) bool {
+			return v == 1
+		})
+	}()
+
+	c.Reset()
+	c.Add(1)
+
+	assert.NoError(t, <-done)
+}